@@ -0,0 +1,56 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import "sync"
+
+// Clipboard holds text copied and cut by widgets, to be pasted later. Toolkit windows implement it with the
+// system's clipboard.
+type Clipboard interface {
+	// ClipboardString returns the clipboard's text, or "" if it doesn't hold any
+	ClipboardString() string
+	// SetClipboardString replaces the clipboard's text with s
+	SetClipboardString(s string)
+}
+
+// MemoryClipboard is a Clipboard which only exists in memory, for running headless and in tests. Its zero
+// value is an empty clipboard. It's thread-safe.
+type MemoryClipboard struct {
+	lock sync.Mutex
+	s    string
+}
+
+// ClipboardString returns c's text
+func (c *MemoryClipboard) ClipboardString() string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.s
+}
+
+// SetClipboardString replaces c's text with s
+func (c *MemoryClipboard) SetClipboardString(s string) {
+	c.lock.Lock()
+	c.s = s
+	c.lock.Unlock()
+}
+
+// ClipboardUser is implemented by widgets which copy, cut or paste text. WidgetCollection passes its
+// Clipboard on to every ClipboardUser registered with it.
+type ClipboardUser interface {
+	// SetClipboard sets the Clipboard the widget copies to and pastes from
+	SetClipboard(c Clipboard)
+}
+
+// SetClipboard sets the Clipboard used by the collection's widgets, including ones registered later
+func (wc *WidgetCollection) SetClipboard(c Clipboard) {
+	wc.clipboard = c
+	for _, w := range wc.widgets {
+		if cu, ok := w.(ClipboardUser); ok {
+			cu.SetClipboard(c)
+		}
+	}
+}
+
+// Clipboard returns the collection's Clipboard
+func (wc *WidgetCollection) Clipboard() Clipboard {
+	return wc.clipboard
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import "testing"
+
+// clipboardWidget is a dummyWidget recording its Clipboard
+type clipboardWidget struct {
+	*dummyWidget
+	clipboard Clipboard
+}
+
+func (cw *clipboardWidget) SetClipboard(c Clipboard) { cw.clipboard = c }
+
+// clipboardWindow is a dummyWindow with its own clipboard, like a toolkit's window
+type clipboardWindow struct {
+	dummyWindow
+	MemoryClipboard
+}
+
+func TestClipboard(t *testing.T) {
+	c := &MemoryClipboard{}
+	c.SetClipboardString("copied")
+	if s := c.ClipboardString(); s != "copied" {
+		t.Errorf("The clipboard holds %q, should hold %q.", s, "copied")
+	}
+
+	cw := &clipboardWidget{dummyWidget: newDummyWidget("a", 0, 0, 10, 10, nil)}
+	wc := NewWidgetCollection(nil, nil, cw)
+	if _, ok := cw.clipboard.(*MemoryClipboard); !ok {
+		t.Error("Registering should give widgets a memory clipboard when running headless.")
+	}
+	wc.SetClipboard(c)
+	if cw.clipboard != c || wc.Clipboard() != c {
+		t.Error("SetClipboard should pass the clipboard on to widgets.")
+	}
+
+	window := &clipboardWindow{}
+	NewWidgetCollection(nil, window, cw)
+	if cw.clipboard != window {
+		t.Error("Windows implementing Clipboard should be the collection's clipboard.")
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells widgets the time. WidgetCollection passes its Clock on to every Clocked widget, so everything
+// depending on time, such as blinking text cursors, can be tested with a FakeClock.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// RealClock returns a Clock telling the system's time. It's the default Clock of WidgetCollections.
+func RealClock() Clock {
+	return realClock{}
+}
+
+// FakeClock is a Clock which only moves when it's told to. It's thread-safe.
+type FakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+// NewFakeClock creates a FakeClock stopped at now
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the time c is stopped at
+func (c *FakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// Advance moves c forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	c.now = c.now.Add(d)
+	c.lock.Unlock()
+}
+
+// Clocked is implemented by widgets whose behavior depends on time. WidgetCollection passes its Clock on to
+// every Clocked widget registered with it.
+type Clocked interface {
+	// SetClock sets the Clock the widget tells the time with
+	SetClock(c Clock)
+}
+
+// SetClock sets the Clock used by the collection's widgets, including ones registered later
+func (wc *WidgetCollection) SetClock(c Clock) {
+	wc.clock = c
+	for _, w := range wc.widgets {
+		if clocked, ok := w.(Clocked); ok {
+			clocked.SetClock(c)
+		}
+	}
+}
+
+// Clock returns the collection's Clock
+func (wc *WidgetCollection) Clock() Clock {
+	return wc.clock
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import (
+	"testing"
+	"time"
+)
+
+// clockedWidget is a dummyWidget recording its Clock
+type clockedWidget struct {
+	*dummyWidget
+	clock Clock
+}
+
+func (cw *clockedWidget) SetClock(c Clock) { cw.clock = c }
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+	c.Advance(time.Second)
+	if !c.Now().Equal(start.Add(time.Second)) {
+		t.Errorf("The clock is at %v, should be at %v.", c.Now(), start.Add(time.Second))
+	}
+
+	cw := &clockedWidget{dummyWidget: newDummyWidget("a", 0, 0, 10, 10, nil)}
+	wc := NewWidgetCollection(nil, nil, cw)
+	if cw.clock != RealClock() {
+		t.Error("Registering should give widgets the real clock.")
+	}
+	wc.SetClock(c)
+	if cw.clock != c || wc.Clock() != c {
+		t.Error("SetClock should pass the clock on to widgets.")
+	}
+}
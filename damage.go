@@ -0,0 +1,116 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import (
+	"math"
+
+	"github.com/llgcode/draw2d"
+)
+
+// damageMargin is how far outside of its dimensions a widget's border may be drawn, e.g. by antialiasing
+const damageMargin = 2
+
+// Rect is a rectangle from X0, Y0 to X1, Y1
+type Rect struct {
+	X0, Y0, X1, Y1 float64
+}
+
+// Empty checks if r has no area
+func (r Rect) Empty() bool {
+	return r.X0 >= r.X1 || r.Y0 >= r.Y1
+}
+
+// Overlaps checks if r and o share any area
+func (r Rect) Overlaps(o Rect) bool {
+	return !r.Intersect(o).Empty()
+}
+
+// Intersect returns the area r and o share
+func (r Rect) Intersect(o Rect) Rect {
+	return Rect{math.Max(r.X0, o.X0), math.Max(r.Y0, o.Y0), math.Min(r.X1, o.X1), math.Min(r.Y1, o.Y1)}
+}
+
+// Union returns the smallest Rect containing both r and o
+func (r Rect) Union(o Rect) Rect {
+	if r.Empty() {
+		return o
+	} else if o.Empty() {
+		return r
+	}
+	return Rect{math.Min(r.X0, o.X0), math.Min(r.Y0, o.Y0), math.Max(r.X1, o.X1), math.Max(r.Y1, o.Y1)}
+}
+
+// widgetBounds returns the area of the screen w may draw to
+func widgetBounds(w Widget) Rect {
+	x, y := w.GetPos()
+	width, height := w.GetDimensions()
+	return Rect{x - damageMargin, y - damageMargin, x + width + damageMargin, y + height + damageMargin}
+}
+
+// Redrawer is implemented by widgets which can tell when they need to be redrawn. WidgetCollection only
+// redraws the parts of the screen which changed, and uses this to find them. Widgets which don't implement it
+// are redrawn, along with everything overlapping them, on every WidgetCollection.Draw.
+type Redrawer interface {
+	// NeedsRedraw returns whether the widget's appearance changed since it was last drawn
+	NeedsRedraw() bool
+}
+
+// Damager is implemented by Redrawers which can tell which of their parts need to be redrawn, such as panels
+// whose children changed. WidgetCollection only redraws those parts, instead of the whole widget.
+type Damager interface {
+	// Damage returns the areas of the screen the widget needs redrawn. It's called when NeedsRedraw is true.
+	Damage() []Rect
+}
+
+// Clipper is implemented by GraphicContexts which can restrict drawing to a rectangle. WidgetCollection uses
+// it to clip each widget to its bounds and to the damaged parts of the screen. The clip rectangle must be
+// saved and restored along with the rest of the GraphicContext's state by Save and Restore.
+type Clipper interface {
+	// ClipRect restricts drawing to the part of the current clip rectangle inside of x0, y0, x1, y1, which
+	// are transformed by the current matrix.
+	ClipRect(x0, y0, x1, y1 float64)
+}
+
+// ClipStack tracks a clip rectangle in device coordinates through Save and Restore. It's meant to help
+// GraphicContexts implement Clipper.
+type ClipStack struct {
+	clip    Rect
+	clipped bool
+	stack   []clipState
+}
+
+type clipState struct {
+	clip    Rect
+	clipped bool
+}
+
+// Push saves the current clip rectangle, it should be called by Save
+func (cs *ClipStack) Push() {
+	cs.stack = append(cs.stack, clipState{cs.clip, cs.clipped})
+}
+
+// Pop restores the last saved clip rectangle, it should be called by Restore
+func (cs *ClipStack) Pop() {
+	if len(cs.stack) == 0 {
+		return
+	}
+	s := cs.stack[len(cs.stack)-1]
+	cs.stack = cs.stack[:len(cs.stack)-1]
+	cs.clip, cs.clipped = s.clip, s.clipped
+}
+
+// Intersect transforms x0, y0, x1, y1 by tr and intersects the current clip rectangle with it
+func (cs *ClipStack) Intersect(tr draw2d.Matrix, x0, y0, x1, y1 float64) {
+	x0, y0 = tr.TransformPoint(x0, y0)
+	x1, y1 = tr.TransformPoint(x1, y1)
+	r := Rect{math.Min(x0, x1), math.Min(y0, y1), math.Max(x0, x1), math.Max(y0, y1)}
+	if cs.clipped {
+		r = cs.clip.Intersect(r)
+	}
+	cs.clip, cs.clipped = r, true
+}
+
+// Clip returns the current clip rectangle, and whether there is one
+func (cs *ClipStack) Clip() (Rect, bool) {
+	return cs.clip, cs.clipped
+}
@@ -4,13 +4,15 @@ package draw2dui
 
 import (
 	"fmt"
-	"github.com/go-gl/glfw/v3.1/glfw"
 	"sync/atomic"
 )
 
 var widgetCount int32
 
-// Widget is an interface for draw2dui widgets
+// Widget is an interface for draw2dui widgets. It only covers what every widget needs to be drawn and to
+// handle events; widgets support more through optional interfaces, such as TextValue, IntValue, DataValue,
+// ValueWidget, Enabler, Focusable, Scroller, DropTarget, Redrawer, Deadliner, Clocked, ClipboardUser and
+// Themed.
 type Widget interface {
 	// Name returns the widget's name
 	Name() string
@@ -21,13 +23,13 @@ type Widget interface {
 	// not. Returns if it needs a draw or not.
 	Handle(selected bool) bool
 	// KeyPress has the widget process a KeyPress event
-	KeyPress(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) Event
+	KeyPress(key Key, action Action, mods ModifierKey) Event
 	// CharPress has the widget process a character
 	CharPress(char rune) Event
 	// MMove has the widget process a MouseMove event
 	MMove(xpos, ypos float64) Event
 	// MClick has the widget process a MouseClick event
-	MClick(xpos, ypos float64, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) Event
+	MClick(xpos, ypos float64, button MouseButton, action Action, mods ModifierKey) Event
 	// SetPos changes the widget's x, y coordinates
 	SetPos(x, y float64)
 	// GetPos retrieves the widget's x, y coordinates
@@ -38,25 +40,74 @@ type Widget interface {
 	GetDimensions() (float64, float64)
 	// IsInside checks if point x, y is inside of the widget's boundaries
 	IsInside(x, y float64) bool
-	// SetString sets the widget's string, if it supports it
+}
+
+// TextValue is implemented by widgets holding a string, such as text fields and labels
+type TextValue interface {
+	// SetString sets the widget's string
 	SetString(s string)
-	// GetString returns a string, if the widget supports it
+	// GetString returns the widget's string
 	GetString() string
-	// SetInt sets the widget's int, if it supports it
+}
+
+// IntValue is implemented by widgets holding an int
+type IntValue interface {
+	// SetInt sets the widget's int
 	SetInt(i int)
-	// GetInt returns an int, if the widget supports it
+	// GetInt returns the widget's int
 	GetInt() int
-	// SetData sets the widget's data if it supports it. This must be a type supported by the widget.
+}
+
+// DataValue is implemented by widgets holding arbitrary data. ValueWidget should be preferred for data of a
+// known type.
+type DataValue interface {
+	// SetData sets the widget's data. This must be a type supported by the widget.
 	SetData(d interface{})
-	// GetData returns an interface{}, if the widget supports it
+	// GetData returns the widget's data
 	GetData() interface{}
+}
+
+// Enabler is implemented by widgets which can be disabled. Widgets which don't implement it are always
+// enabled.
+type Enabler interface {
 	// SetEnabled enables or disables the widget
 	SetEnabled(enabled bool)
 	// GetEnabled returns whether the widget is enabled or not
 	GetEnabled() bool
 }
 
-// NameWidget returns a unique widget name. It is thread-safe.
+// IsEnabled checks if w is enabled. Widgets which don't implement Enabler are.
+func IsEnabled(w Widget) bool {
+	if e, ok := w.(Enabler); ok {
+		return e.GetEnabled()
+	}
+	return true
+}
+
+// Focusable is implemented by widgets which can tell whether they accept keyboard focus. Widgets which don't
+// implement it are focusable.
+type Focusable interface {
+	// Focusable returns whether the widget can be selected with the keyboard
+	Focusable() bool
+}
+
+// TabContainer is implemented by widgets holding other widgets which take the keyboard focus, such as
+// panels. When Tab moves the selection to one, WidgetCollection calls TabInto so it can select one of its own.
+type TabContainer interface {
+	// TabInto has the widget select its first focusable child, or its last if reverse is true (Shift+Tab)
+	TabInto(reverse bool)
+}
+
+// Scroller is implemented by widgets which can be scrolled, such as with a mouse wheel or touchpad.
+// WidgetCollection.Scroll sends Scroll events to the topmost widget under the mouse.
+type Scroller interface {
+	// Scroll has the widget process a Scroll event. xoff and yoff are how far to scroll in steps of a mouse
+	// wheel, positive yoff scrolls up and positive xoff scrolls right. Touchpads send fractions of a step.
+	Scroll(xoff, yoff float64) Event
+}
+
+// NameWidget returns a unique widget name. It is thread-safe, see WidgetCollection.Post for using widgets
+// from other goroutines.
 func NameWidget(w string) string {
 	return fmt.Sprintf("%s-%d", w, atomic.AddInt32(&widgetCount, 1))
 }
@@ -81,4 +132,49 @@ const (
 	EventSelected
 	// EventHasCursor means the widget currently controls the cursor
 	EventHasCursor
+	// EventFocusLost means the widget stopped being selected
+	EventFocusLost
 )
+
+// Handlers holds callbacks for a widget's events, which WidgetCollection calls as the events arrive. Nil
+// callbacks are skipped. Widgets can embed Handlers to carry their own callbacks (see HandlerWidget), or they
+// can be set on a collection's entries with WidgetCollection.SetHandlers.
+type Handlers struct {
+	// OnConfirm is called when the user confirms an action (typically they hit enter or clicked a button)
+	OnConfirm func(w Widget)
+	// OnAction is called when the user modifies the widget in some way (changed a dropdown, changed text)
+	OnAction func(w Widget)
+	// OnSelected is called when the widget becomes selected
+	OnSelected func(w Widget)
+	// OnFocusLost is called when the widget stops being selected
+	OnFocusLost func(w Widget)
+}
+
+// GetHandlers returns h, so widgets embedding Handlers implement HandlerWidget
+func (h *Handlers) GetHandlers() *Handlers {
+	return h
+}
+
+// Call calls the callback for event, if there is one
+func (h *Handlers) Call(w Widget, event Event) {
+	var f func(Widget)
+	switch event {
+	case EventConfirm:
+		f = h.OnConfirm
+	case EventAction:
+		f = h.OnAction
+	case EventSelected:
+		f = h.OnSelected
+	case EventFocusLost:
+		f = h.OnFocusLost
+	}
+	if f != nil {
+		f(w)
+	}
+}
+
+// HandlerWidget is implemented by widgets which carry their own Handlers, usually by embedding them
+type HandlerWidget interface {
+	// GetHandlers returns the widget's Handlers
+	GetHandlers() *Handlers
+}
@@ -2,14 +2,12 @@
 package draw2dui
 
 import (
-	"os"
-	"runtime"
+	"image/color"
+	"math"
 	"testing"
 
-	"github.com/go-gl/gl/v2.1/gl"
-	"github.com/go-gl/glfw/v3.1/glfw"
 	"github.com/llgcode/draw2d"
-	"github.com/llgcode/draw2d/draw2dgl"
+	"github.com/llgcode/draw2d/draw2dkit"
 )
 
 var (
@@ -17,161 +15,656 @@ var (
 	gc            draw2d.GraphicContext
 )
 
-// TODO make dummy widgets, use them for tests
+// dummyWidget is a rectangular widget which records the events it receives
+type dummyWidget struct {
+	Handlers
+	name                string
+	x, y, width, height float64
+	enabled, focusable  bool
+	drawn               *[]string // drawn is appended to with name whenever the widget is drawn
+	hovered             bool
+	clickEvent          Event // clickEvent is returned by MClick when it's inside of the widget
+}
 
-func getNewWidgetCollection() (window, offscreen *glfw.Window, wc *WidgetCollection) {
-	width, height = 800, 600
+func newDummyWidget(name string, x, y, w, h float64, drawn *[]string) *dummyWidget {
+	return &dummyWidget{name: name, x: x, y: y, width: w, height: h, enabled: true, focusable: true, drawn: drawn,
+		clickEvent: EventSelected}
+}
 
-	//glfw.WindowHint(glfw.Visible, glfw.True)
-	window, err := glfw.CreateWindow(width, height, "++WidgetCollectionTest++", nil, nil)
-	if err != nil {
-		panic(err)
+func (dw *dummyWidget) Name() string { return dw.name }
+func (dw *dummyWidget) Draw(selected, forceRedraw bool) {
+	if dw.drawn != nil {
+		*dw.drawn = append(*dw.drawn, dw.name)
 	}
-	window.MakeContextCurrent()
-	err = gl.Init()
-	if err != nil {
-		panic(err)
+}
+func (dw *dummyWidget) Handle(selected bool) bool                               { return false }
+func (dw *dummyWidget) KeyPress(key Key, action Action, mods ModifierKey) Event { return EventNone }
+func (dw *dummyWidget) CharPress(char rune) Event                               { return EventNone }
+func (dw *dummyWidget) MMove(xpos, ypos float64) Event {
+	dw.hovered = dw.IsInside(xpos, ypos)
+	if dw.hovered {
+		return EventHasCursor
 	}
-	reshape(width, height)
-
-	//glfw.WindowHint(glfw.Visible, glfw.False)
-	offscreen, err = glfw.CreateWindow(width, height, "Offscreen (You shouldnt see this)", nil, nil)
-	if err != nil {
-		panic(err)
+	return EventNone
+}
+func (dw *dummyWidget) MClick(xpos, ypos float64, button MouseButton, action Action, mods ModifierKey) Event {
+	if action == Press && dw.IsInside(xpos, ypos) {
+		return dw.clickEvent
 	}
-	offscreen.MakeContextCurrent()
-	reshape(width, height)
-
-	window.MakeContextCurrent()
+	return EventNone
+}
+func (dw *dummyWidget) SetPos(x, y float64)               { dw.x, dw.y = x, y }
+func (dw *dummyWidget) GetPos() (float64, float64)        { return dw.x, dw.y }
+func (dw *dummyWidget) SetDimensions(w, h float64)        { dw.width, dw.height = w, h }
+func (dw *dummyWidget) GetDimensions() (float64, float64) { return dw.width, dw.height }
+func (dw *dummyWidget) IsInside(x, y float64) bool {
+	return x >= dw.x && x < dw.x+dw.width && y >= dw.y && y < dw.y+dw.height
+}
+func (dw *dummyWidget) SetEnabled(enabled bool) { dw.enabled = enabled }
+func (dw *dummyWidget) GetEnabled() bool        { return dw.enabled }
+func (dw *dummyWidget) Focusable() bool         { return dw.focusable }
 
-	return window, offscreen, NewWidgetCollection(&gc, window)
+// getNewWidgetCollection returns a headless WidgetCollection drawing into an ImageContext
+func getNewWidgetCollection() *WidgetCollection {
+	width, height = 800, 600
+	gc = NewImageContext(width, height)
+	return NewWidgetCollection(&gc, nil)
 }
 
 func BenchmarkNewWidgetCollection(b *testing.B) {
 	width, height = 800, 600
-
-	//glfw.WindowHint(glfw.Visible, glfw.True)
-	window, err := glfw.CreateWindow(width, height, "++WidgetCollectionTest++", nil, nil)
-	if err != nil {
-		panic(err)
-	}
-	window.MakeContextCurrent()
-	reshape(width, height)
+	gc = NewImageContext(width, height)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		NewWidgetCollection(&gc, window)
+		NewWidgetCollection(&gc, nil)
 	}
-	b.StopTimer()
-	window.Destroy()
 }
 
 func TestWidgetCollectionDraw(t *testing.T) {
-	window, offscreen, wc := getNewWidgetCollection()
+	wc := getNewWidgetCollection()
 	wc.forceRedraw = true
 	wc.Draw()
 	if wc.forceRedraw {
 		t.Fail()
 	}
-
-	window.Destroy()
-	offscreen.Destroy()
 }
 
 func TestWidgetCollectionHandle(t *testing.T) {
-	window, offscreen, wc := getNewWidgetCollection()
+	wc := getNewWidgetCollection()
 	wc.forceRedraw = true
 
 	if wc.Handle() {
 		t.Error("Handle shouldn't request a redraw.")
 	}
-
-	window.Destroy()
-	offscreen.Destroy()
 }
 
 func TestWidgetCollectionKeyPress(t *testing.T) {
-	window, offscreen, wc := getNewWidgetCollection()
+	wc := getNewWidgetCollection()
 	wc.forceRedraw = true
 
 	w, ev := wc.KeyPress(0, 0, 0)
 	if w != nil || ev != EventNone {
 		t.Fail()
 	}
-
-	window.Destroy()
-	offscreen.Destroy()
 }
 
 func TestWidgetCollectionCharPress(t *testing.T) {
-	window, offscreen, wc := getNewWidgetCollection()
+	wc := getNewWidgetCollection()
 	wc.forceRedraw = true
 
 	w, ev := wc.CharPress(0)
 	if w != nil || ev != EventNone {
 		t.Fail()
 	}
-
-	window.Destroy()
-	offscreen.Destroy()
 }
 
 func TestWidgetCollectionMMove(t *testing.T) {
-	window, offscreen, wc := getNewWidgetCollection()
+	wc := getNewWidgetCollection()
 	wc.forceRedraw = true
 
 	w, ev := wc.MMove(0, 0)
 	if w != nil || ev != EventNone || !wc.hasCursor {
 		t.Fail()
 	}
-
-	window.Destroy()
-	offscreen.Destroy()
 }
 
 func TestWidgetCollectionMClick(t *testing.T) {
-	window, offscreen, wc := getNewWidgetCollection()
+	wc := getNewWidgetCollection()
 	wc.forceRedraw = false
 
-	w, ev := wc.MClick(0, glfw.Release, 0)
-	if w != nil || ev != EventNone || wc.forceRedraw {
+	w, ev := wc.MClick(0, Release, 0)
+	if w != nil || ev != EventNone || len(wc.damage) != 0 {
 		t.Error("Failed Release test.")
 	}
 
 	wc.selected = "test"
-	w, ev = wc.MClick(0, glfw.Press, 0)
-	if w != nil || ev != EventSelected || !wc.forceRedraw || wc.selected != "" {
+	w, ev = wc.MClick(0, Press, 0)
+	if w != nil || ev != EventSelected || wc.selected != "" {
 		t.Error("Failed Press test.")
 	}
-
-	window.Destroy()
-	offscreen.Destroy()
 }
 
 func TestWidgetCollectionReshape(t *testing.T) {
-	window, offscreen, wc := getNewWidgetCollection()
+	wc := getNewWidgetCollection()
 	wc.forceRedraw = false
 
 	wc.Reshape(0, 0)
 	if !wc.forceRedraw {
 		t.Fail()
 	}
-
-	window.Destroy()
-	offscreen.Destroy()
 }
 
 func TestWidgetCollectionRefresh(t *testing.T) {
-	window, offscreen, wc := getNewWidgetCollection()
+	wc := getNewWidgetCollection()
 	wc.forceRedraw = false
 
 	wc.Refresh()
 	if !wc.forceRedraw {
 		t.Fail()
 	}
+}
+
+// getOverlappingWidgets returns a headless collection holding three overlapping dummy widgets, a, b and c,
+// from the bottom up
+func getOverlappingWidgets(drawn *[]string) (wc *WidgetCollection, a, b, c *dummyWidget) {
+	wc = getNewWidgetCollection()
+	a = newDummyWidget("a", 0, 0, 100, 100, drawn)
+	b = newDummyWidget("b", 50, 50, 100, 100, drawn)
+	c = newDummyWidget("c", 75, 75, 100, 100, drawn)
+	wc.Register(a)
+	wc.Register(b)
+	wc.Register(c)
+	return
+}
+
+// isDamaged checks if any of w's area will be redrawn by wc's next Draw
+func isDamaged(wc *WidgetCollection, w Widget) bool {
+	for _, r := range wc.damage {
+		if r.Overlaps(widgetBounds(w)) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkOrder(t *testing.T, got []string, want ...string) {
+	if len(got) != len(want) {
+		t.Errorf("Order is %v, should be %v.", got, want)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("Order is %v, should be %v.", got, want)
+			return
+		}
+	}
+}
+
+func widgetNames(widgets []Widget) (names []string) {
+	for _, w := range widgets {
+		names = append(names, w.Name())
+	}
+	return
+}
+
+func TestWidgetCollectionOrder(t *testing.T) {
+	var drawn []string
+	wc, _, _, _ := getOverlappingWidgets(&drawn)
+	for i := 0; i < 10; i++ {
+		drawn = drawn[:0]
+		wc.Draw()
+		checkOrder(t, drawn, "a", "b", "c")
+	}
+
+	if !wc.Raise("a") || !isDamaged(wc, wc.Get("a")) {
+		t.Error("Raise should succeed and redraw a.")
+	}
+	checkOrder(t, widgetNames(wc.Widgets()), "b", "c", "a")
+	wc.Lower("c")
+	checkOrder(t, widgetNames(wc.Widgets()), "c", "b", "a")
+	wc.MoveTo("a", 1)
+	checkOrder(t, widgetNames(wc.Widgets()), "c", "a", "b")
+	wc.SetOrder("b", "missing", "c")
+	checkOrder(t, widgetNames(wc.Widgets()), "b", "c", "a")
+	if wc.Raise("missing") || wc.Index("missing") != -1 || wc.Get("missing") != nil {
+		t.Error("Missing widgets shouldn't be found.")
+	}
+	if wc.Index("c") != 1 || wc.Get("c") == nil {
+		t.Error("c should be found.")
+	}
+	wc.SetOrder("missing", "a", "b")
+	checkOrder(t, widgetNames(wc.Widgets()), "a", "b", "c")
+}
+
+func TestWidgetCollectionTopmost(t *testing.T) {
+	wc, a, b, c := getOverlappingWidgets(nil)
+
+	w, _ := wc.MMove(80, 80)
+	if w != c || !c.hovered || b.hovered || a.hovered {
+		t.Error("Only c should be moused-over.")
+	}
+	w, ev := wc.MClick(MouseButtonLeft, Press, 0)
+	if w != c || ev != EventSelected || wc.selected != "c" {
+		t.Error("c should be clicked.")
+	}
+
+	wc.Raise("a")
+	w, _ = wc.MMove(80, 80)
+	if w != a || !a.hovered || b.hovered || c.hovered {
+		t.Error("Only a should be moused-over.")
+	}
+	w, _ = wc.MClick(MouseButtonLeft, Press, 0)
+	if w != a || wc.selected != "a" {
+		t.Error("a should be clicked.")
+	}
+
+	w, _ = wc.MMove(140, 140)
+	if w != c {
+		t.Error("c should be moused-over.")
+	}
+}
+
+// clickWidget is a dummyWidget recording where it was last clicked
+type clickWidget struct {
+	*dummyWidget
+	clickX float64
+}
+
+func (cw *clickWidget) MClick(xpos, ypos float64, button MouseButton, action Action, mods ModifierKey) Event {
+	cw.clickX = xpos
+	return cw.dummyWidget.MClick(xpos, ypos, button, action, mods)
+}
+
+func TestWidgetCollectionClickTopmost(t *testing.T) {
+	wc, a, b, c := getOverlappingWidgets(nil)
+	ca := &clickWidget{dummyWidget: a}
+	wc.Replace("a", ca)
+	c.clickEvent = EventNone
+	wc.Select("b")
+	wc.MMove(80, 80)
+	if w, ev := wc.MClick(MouseButtonLeft, Press, 0); w != nil || ev != EventSelected || wc.selected != "" {
+		t.Error("Clicks on a widget which ignores them shouldn't reach the widgets under it.")
+	}
+	wc.MMove(60, 60)
+	if w, _ := wc.MClick(MouseButtonLeft, Release, 0); w != nil || !math.IsInf(ca.clickX, -1) {
+		t.Error("Widgets which aren't clicked should be sent a position outside of the screen.")
+	}
+	if w, ev := wc.MClick(MouseButtonLeft, Press, 0); w != b || ev != EventSelected {
+		t.Error("b should be clicked.")
+	}
+}
 
-	window.Destroy()
-	offscreen.Destroy()
+// dummyWindow records the cursor shape it was last set to
+type dummyWindow struct {
+	cursor CursorShape
+}
+
+func (dw *dummyWindow) SetCursor(shape CursorShape) {
+	dw.cursor = shape
+}
+
+func TestWidgetCollectionNilWindow(t *testing.T) {
+	width, height = 800, 600
+	gc = NewImageContext(width, height)
+	wc := NewWidgetCollection(&gc, (*dummyWindow)(nil), newDummyWidget("a", 10, 10, 50, 50, nil))
+	if wc.window != nil {
+		t.Error("A nil pointer window should be stored as nil.")
+	}
+	if _, ok := wc.Clipboard().(*MemoryClipboard); !ok {
+		t.Error("A nil pointer window should get a MemoryClipboard.")
+	}
+	wc.MMove(20, 20)
+	wc.MMove(100, 100)
+	wc.Remove("a")
+}
+
+func TestWidgetCollectionRemove(t *testing.T) {
+	wc, a, b, c := getOverlappingWidgets(nil)
+	window := &dummyWindow{cursor: HandCursor}
+	wc.window = window
+	wc.MMove(80, 80)
+	wc.MClick(MouseButtonLeft, Press, 0)
+	wc.Draw()
+
+	if wc.Remove("missing") != nil {
+		t.Error("Removing a missing widget should return nil.")
+	}
+	if wc.Remove("c") != c {
+		t.Error("Remove should return the removed widget.")
+	}
+	checkOrder(t, widgetNames(wc.Widgets()), "a", "b")
+	if wc.selected != "" || wc.hovered != "" || !isDamaged(wc, c) {
+		t.Error("Removing c should deselect it and clear its area.")
+	}
+	if window.cursor != ArrowCursor || !wc.hasCursor {
+		t.Error("Removing c should reset the cursor.")
+	}
+	if w, _ := wc.MClick(MouseButtonLeft, Press, 0); w != b {
+		t.Error("b should be clicked after c is removed.")
+	}
+
+	d := newDummyWidget("d", 0, 0, 10, 10, nil)
+	if wc.Replace("missing", d) {
+		t.Error("Replacing a missing widget should fail.")
+	}
+	if !wc.Replace("b", d) || wc.Get("b") != nil {
+		t.Error("Replace should remove b.")
+	}
+	checkOrder(t, widgetNames(wc.Widgets()), "a", "d")
+	if wc.selected != "d" {
+		t.Error("Replacing b should select d.")
+	}
+	if w, _ := wc.MClick(MouseButtonLeft, Press, 0); w != a {
+		t.Error("a should be clicked after b is replaced.")
+	}
+}
+
+func TestWidgetCollectionTab(t *testing.T) {
+	wc, a, b, c := getOverlappingWidgets(nil)
+	d := newDummyWidget("d", 0, 0, 10, 10, nil)
+	wc.Register(d)
+	wc.Select("")
+
+	check := func(mods ModifierKey, want Widget) {
+		wc.Draw()
+		w, ev := wc.KeyPress(KeyTab, Press, mods)
+		if w != want || ev != EventSelected || wc.Selected() != want || !isDamaged(wc, want) {
+			t.Errorf("Tab selected %v, should have selected %s.", w, want.Name())
+		}
+	}
+	check(0, a)
+	check(0, b)
+	check(0, c)
+	check(0, d)
+	check(0, a)
+	check(ModShift, d)
+
+	b.enabled = false
+	c.focusable = false
+	check(0, a)
+	check(0, d)
+	check(ModShift, a)
+
+	wc.SetTabOrder("d", "c", "missing", "b", "a")
+	b.enabled = true
+	check(ModShift, b)
+	check(ModShift, d)
+	check(0, b)
+
+	wc.SetTabOrder()
+	check(0, d)
+	if w, ev := wc.KeyPress(KeyTab, Release, 0); w != nil || ev != EventNone || wc.Selected() != d {
+		t.Error("Releasing Tab shouldn't change the selection.")
+	}
+}
+
+func TestWidgetCollectionHandlers(t *testing.T) {
+	wc, a, b, c := getOverlappingWidgets(nil)
+	wc.Select("a")
+	var got []string
+	record := func(prefix string) func(Widget) {
+		return func(w Widget) {
+			got = append(got, prefix+w.Name())
+		}
+	}
+	wc.SetHandlers("b", Handlers{OnSelected: record("selected "), OnFocusLost: record("lost "),
+		OnConfirm: record("confirm ")})
+	a.OnFocusLost = record("own lost ")
+	c.OnConfirm = record("own confirm ")
+	c.clickEvent = EventConfirm
+
+	wc.KeyPress(KeyTab, Press, 0)
+	wc.MMove(80, 80)
+	wc.MClick(MouseButtonLeft, Press, 0)
+	b.clickEvent = EventConfirm
+	wc.MMove(60, 60)
+	wc.MClick(MouseButtonLeft, Press, 0)
+	wc.Remove("b")
+	checkOrder(t, got, "own lost a", "selected b", "own confirm c", "confirm b", "lost b")
+
+	if wc.GetHandlers("b").OnSelected != nil {
+		t.Error("Removing b should forget its handlers.")
+	}
+}
+
+// redrawWidget is a dummyWidget which implements Redrawer
+type redrawWidget struct {
+	*dummyWidget
+	redraw bool
+}
+
+func (rw *redrawWidget) NeedsRedraw() bool { return rw.redraw }
+func (rw *redrawWidget) Draw(selected, forceRedraw bool) {
+	rw.dummyWidget.Draw(selected, forceRedraw)
+	rw.redraw = false
+}
+
+func newRedrawWidget(name string, x, y, w, h float64, drawn *[]string) *redrawWidget {
+	return &redrawWidget{newDummyWidget(name, x, y, w, h, drawn), true}
+}
+
+// noClipContext hides an ImageContext's ClipRect method
+type noClipContext struct {
+	draw2d.GraphicContext
+}
+
+func TestWidgetCollectionDamage(t *testing.T) {
+	var drawn []string
+	wc := getNewWidgetCollection()
+	a := newRedrawWidget("a", 0, 0, 100, 100, &drawn)
+	b := newRedrawWidget("b", 50, 50, 100, 100, &drawn)
+	c := newRedrawWidget("c", 75, 75, 100, 100, &drawn)
+	d := newRedrawWidget("d", 170, 170, 10, 10, &drawn)
+	wc.Register(a)
+	wc.Register(b)
+	wc.Register(c)
+	wc.Register(d)
+	draw := func(want ...string) {
+		drawn = drawn[:0]
+		wc.Draw()
+		checkOrder(t, drawn, want...)
+	}
+
+	draw("a", "b", "c", "d")
+	draw()
+	b.redraw = true
+	draw("a", "b", "c")
+	d.SetPos(400, 400)
+	draw("c", "d")
+	wc.Invalidate(0, 0, 10, 10)
+	draw("a")
+
+	img := gc.(*ImageContext).Image
+	img.Set(405, 405, color.Black)
+	wc.Remove("d")
+	draw()
+	if r, g, b, _ := img.At(405, 405).RGBA(); r != 0xffff || g != 0xffff || b != 0xffff {
+		t.Error("Removing d should clear its area.")
+	}
+
+	// Without clipping, damage grows to cover every widget it touches, so redrawing b also redraws d
+	wc.Register(newRedrawWidget("d", 170, 170, 10, 10, &drawn))
+	gc = noClipContext{gc}
+	draw("a", "b", "c", "d")
+	b.redraw = true
+	draw("a", "b", "c", "d")
+}
+
+func TestImageContextClipRect(t *testing.T) {
+	ic := NewImageContext(100, 100)
+	fill := func() {
+		ic.SetFillColor(color.Black)
+		ic.BeginPath()
+		draw2dkit.Rectangle(ic, 0, 0, 100, 100)
+		ic.Fill()
+	}
+	isBlack := func(x, y int) bool {
+		r, _, _, _ := ic.Image.At(x, y).RGBA()
+		return r == 0
+	}
+
+	ic.Save()
+	ic.Translate(10, 10)
+	ic.ClipRect(0, 0, 20, 20)
+	ic.ClipRect(10, 10, 50, 50)
+	fill()
+	if !isBlack(25, 25) || isBlack(15, 15) || isBlack(35, 35) {
+		t.Error("Drawing should be clipped to 20, 20, 30, 30.")
+	}
+	ic.Restore()
+	fill()
+	if !isBlack(5, 5) || !isBlack(95, 95) {
+		t.Error("Restore should remove the clip rectangle.")
+	}
+}
+
+// scrollWidget is a dummyWidget recording how far it was scrolled
+type scrollWidget struct {
+	*dummyWidget
+	scrolled float64
+}
+
+func (sw *scrollWidget) Scroll(xoff, yoff float64) Event {
+	sw.scrolled += yoff
+	return EventAction
+}
+
+func TestWidgetCollectionScroll(t *testing.T) {
+	wc, a, b, _ := getOverlappingWidgets(nil)
+	sa := &scrollWidget{dummyWidget: a}
+	sb := &scrollWidget{dummyWidget: b}
+	wc.Replace("a", sa)
+	wc.Replace("b", sb)
+	wc.MMove(60, 60)
+	if w, event := wc.Scroll(0, 1); w != sb || event != EventAction || sb.scrolled != 1 || sa.scrolled != 0 {
+		t.Error("The topmost widget under the mouse should be scrolled.")
+	}
+	wc.MMove(80, 80)
+	if w, _ := wc.Scroll(0, 1); w != nil || sb.scrolled != 1 {
+		t.Error("Widgets covered by one which doesn't scroll shouldn't be scrolled.")
+	}
+	b.enabled = false
+	wc.MMove(60, 60)
+	if w, _ := wc.Scroll(0, 1); w != nil || sb.scrolled != 1 {
+		t.Error("Disabled widgets shouldn't be scrolled.")
+	}
+}
+
+// keyWidget is a dummyWidget confirming on every key and acting on every character
+type keyWidget struct {
+	*dummyWidget
+	confirmed, typed int
+}
+
+func (kw *keyWidget) KeyPress(key Key, action Action, mods ModifierKey) Event {
+	if key == KeyTab {
+		return EventNone
+	}
+	kw.confirmed++
+	return EventConfirm
+}
+
+func (kw *keyWidget) CharPress(char rune) Event {
+	kw.typed++
+	return EventAction
+}
+
+func TestWidgetCollectionDisabled(t *testing.T) {
+	wc, _, b, _ := getOverlappingWidgets(nil)
+	kb := &keyWidget{dummyWidget: b}
+	wc.Replace("b", kb)
+	confirmed := 0
+	wc.SetHandlers("b", Handlers{OnConfirm: func(Widget) { confirmed++ }})
+	wc.Select("b")
+	b.enabled = false
+
+	if w, ev := wc.KeyPress(KeyEnter, Press, 0); w != nil || ev != EventNone || kb.confirmed != 0 || confirmed != 0 {
+		t.Error("Disabled widgets shouldn't process keys.")
+	}
+	if w, ev := wc.CharPress('a'); w != nil || ev != EventNone || kb.typed != 0 {
+		t.Error("Disabled widgets shouldn't process characters.")
+	}
+	if w, _ := wc.KeyPress(KeyTab, Press, 0); w == b {
+		t.Error("Tab should move the selection away from a disabled widget.")
+	}
+	wc.MMove(60, 60)
+	if w, ev := wc.MClick(MouseButtonLeft, Press, 0); w != nil || ev != EventNone || wc.selected == "a" {
+		t.Error("Clicking a disabled widget shouldn't click the widget under it.")
+	}
+
+	b.enabled = true
+	wc.Select("b")
+	if w, ev := wc.KeyPress(KeyEnter, Press, 0); w != kb || ev != EventConfirm || confirmed != 1 {
+		t.Error("Enabled widgets should process keys.")
+	}
+}
+
+// dropWidget is a dummyWidget recording drag and drop events
+type dropWidget struct {
+	*dummyWidget
+	over    bool
+	dropped []string
+}
+
+func (dw *dropWidget) DragOver(xpos, ypos float64, paths []string) bool {
+	dw.over = true
+	return true
+}
+
+func (dw *dropWidget) DragLeave() {
+	dw.over = false
+}
+
+func (dw *dropWidget) Drop(xpos, ypos float64, paths []string) Event {
+	dw.over = false
+	dw.dropped = paths
+	return EventAction
+}
+
+func TestWidgetCollectionDrop(t *testing.T) {
+	wc, a, b, _ := getOverlappingWidgets(nil)
+	da := &dropWidget{dummyWidget: a}
+	db := &dropWidget{dummyWidget: b}
+	wc.Replace("a", da)
+	wc.Replace("b", db)
+	paths := []string{"file"}
+	if w := wc.DragOver(60, 60, paths); w != db || !db.over || da.over {
+		t.Error("The topmost widget under the drag should be dragged over.")
+	}
+	if w := wc.DragOver(20, 20, paths); w != da || !da.over || db.over {
+		t.Error("Widgets should be sent DragLeave when the drag moves to another widget.")
+	}
+	if w := wc.DragOver(80, 80, paths); w != nil || da.over {
+		t.Error("Widgets covered by one which doesn't accept drops shouldn't be dragged over.")
+	}
+	wc.DragOver(20, 20, paths)
+	if w, event := wc.Drop(60, 60, paths); w != db || event != EventAction || len(db.dropped) != 1 || da.over {
+		t.Error("Files should be dropped on the topmost widget under them, and the others sent DragLeave.")
+	}
+	b.enabled = false
+	if w, _ := wc.Drop(60, 60, paths); w != nil {
+		t.Error("Disabled widgets shouldn't be dropped on.")
+	}
+}
+
+// minimalWidget implements nothing but Widget
+type minimalWidget struct {
+	Widget
+}
+
+func TestIsEnabled(t *testing.T) {
+	dw := newDummyWidget("a", 0, 0, 10, 10, nil)
+	dw.enabled = false
+	if IsEnabled(dw) || !IsEnabled(minimalWidget{dw}) {
+		t.Error("Only widgets implementing Enabler can be disabled.")
+	}
+	wc := getNewWidgetCollection()
+	wc.Register(minimalWidget{dw})
+	wc.Select("")
+	if w, _ := wc.KeyPress(KeyTab, Press, 0); w == nil || w.Name() != "a" {
+		t.Error("Widgets implementing nothing but Widget should be focusable.")
+	}
 }
 
 func TestNameWidget(t *testing.T) {
@@ -180,40 +673,3 @@ func TestNameWidget(t *testing.T) {
 		t.Fail()
 	}
 }
-
-func TestMain(m *testing.M) {
-	r := m.Run()
-	glfw.Terminate()
-	os.Exit(r)
-}
-
-func reshape(w, h int) {
-	gl.ClearColor(1, 1, 1, 1)
-	/* Establish viewing area to cover entire window. */
-	gl.Viewport(0, 0, int32(w), int32(h))
-	/* PROJECTION Matrix mode. */
-	gl.MatrixMode(gl.PROJECTION)
-	/* Reset project matrix. */
-	gl.LoadIdentity()
-	/* Map abstract coords directly to window coords. */
-	gl.Ortho(0, float64(w), 0, float64(h), -1, 1)
-	/* Invert Y axis so increasing Y goes down. */
-	gl.Scalef(1, -1, 1)
-	/* Shift origin up to upper-left corner. */
-	gl.Translatef(0, float32(-h), 0)
-	gl.Enable(gl.BLEND)
-	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
-	gl.Disable(gl.DEPTH_TEST)
-	width, height = w, h
-	/* Recreate graphic context with new width & height. */
-	gc = draw2dgl.NewGraphicContext(width, height)
-}
-
-func init() {
-	runtime.LockOSThread()
-	err := glfw.Init()
-	if err != nil {
-		panic(err)
-	}
-	glfw.WindowHint(glfw.Visible, glfw.False)
-}
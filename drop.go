@@ -0,0 +1,78 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+// DropTarget is implemented by widgets which accept files dropped on them. WidgetCollection sends drag and
+// drop events to the topmost widget under the dragged files.
+type DropTarget interface {
+	// DragOver is sent while files with paths are dragged over the widget at xpos, ypos. It returns whether
+	// the widget would accept them, which it may show with a highlight until DragLeave or Drop.
+	DragOver(xpos, ypos float64, paths []string) bool
+	// DragLeave is sent when the dragged files leave the widget, or the drag is cancelled
+	DragLeave()
+	// Drop has the widget process files with paths dropped at xpos, ypos
+	Drop(xpos, ypos float64, paths []string) Event
+}
+
+// dropTargetAt returns the topmost widget at xpos, ypos if it's an enabled DropTarget
+func (wc *WidgetCollection) dropTargetAt(xpos, ypos float64) (Widget, DropTarget) {
+	for i := len(wc.widgets) - 1; i >= 0; i-- {
+		w := wc.widgets[i]
+		if !w.IsInside(xpos, ypos) {
+			continue
+		}
+		if dt, ok := w.(DropTarget); ok && IsEnabled(w) {
+			return w, dt
+		}
+		break
+	}
+	return nil, nil
+}
+
+// DragOver has the topmost widget under xpos, ypos process a DragOver event for files with paths, returning
+// it if it would accept them. The widget previously dragged over is sent DragLeave if it's a different one.
+// Toolkits which report drags should call it whenever the dragged files move.
+func (wc *WidgetCollection) DragOver(xpos, ypos float64, paths []string) Widget {
+	w, dt := wc.dropTargetAt(xpos, ypos)
+	if w == nil || w.Name() != wc.dragTarget {
+		wc.DragLeave()
+	}
+	if dt == nil {
+		return nil
+	}
+	if !dt.DragOver(xpos, ypos, paths) {
+		wc.DragLeave()
+		return nil
+	}
+	wc.dragTarget = w.Name()
+	return w
+}
+
+// DragLeave sends DragLeave to the widget last dragged over, if any, such as when the drag leaves the window
+func (wc *WidgetCollection) DragLeave() {
+	if wc.dragTarget == "" {
+		return
+	}
+	if dt, ok := wc.Get(wc.dragTarget).(DropTarget); ok {
+		dt.DragLeave()
+	}
+	wc.dragTarget = ""
+}
+
+// Drop has the topmost widget under xpos, ypos process files with paths dropped on it, returning the widget
+// and event if it isn't EventNone. Widgets which were dragged over but weren't dropped on are sent DragLeave.
+func (wc *WidgetCollection) Drop(xpos, ypos float64, paths []string) (Widget, Event) {
+	w, dt := wc.dropTargetAt(xpos, ypos)
+	if w == nil || w.Name() != wc.dragTarget {
+		wc.DragLeave()
+	}
+	wc.dragTarget = ""
+	if dt == nil {
+		return nil, EventNone
+	}
+	event := dt.Drop(xpos, ypos, paths)
+	if event == EventNone {
+		return nil, EventNone
+	}
+	wc.fire(w, event)
+	return w, event
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import "time"
+
+// WaitForEvents is returned by a FramePolicy to have the event loop wait until an event arrives
+const WaitForEvents time.Duration = -1
+
+// FramePolicy decides when an event loop draws frames, such as the one run by glfwadapter.App. See
+// OnDemand, MaxFPS and Continuous.
+type FramePolicy interface {
+	// Schedule is called by the loop each time it has processed events. dirty is whether anything needs to
+	// be redrawn, and last is when the last frame was drawn. It returns whether to draw a frame now, and if
+	// not, how long to wait for events before calling Schedule again, or WaitForEvents.
+	Schedule(now, last time.Time, dirty bool) (draw bool, wait time.Duration)
+}
+
+type onDemand struct{}
+
+func (onDemand) Schedule(now, last time.Time, dirty bool) (bool, time.Duration) {
+	return dirty, WaitForEvents
+}
+
+// OnDemand returns a FramePolicy which draws a frame as soon as anything needs to be redrawn, and waits for
+// events otherwise
+func OnDemand() FramePolicy {
+	return onDemand{}
+}
+
+// maxFPS is a FramePolicy drawing frames on demand, at most once each interval
+type maxFPS struct {
+	interval time.Duration
+}
+
+func (m maxFPS) Schedule(now, last time.Time, dirty bool) (bool, time.Duration) {
+	if !dirty {
+		return false, WaitForEvents
+	}
+	if since := now.Sub(last); since < m.interval {
+		return false, m.interval - since
+	}
+	return true, WaitForEvents
+}
+
+// MaxFPS returns a FramePolicy which draws frames as soon as anything needs to be redrawn, but no more than
+// fps times a second. It waits for events otherwise. If fps isn't positive, it's the same as OnDemand.
+func MaxFPS(fps int) FramePolicy {
+	if fps <= 0 {
+		return onDemand{}
+	}
+	return maxFPS{time.Second / time.Duration(fps)}
+}
+
+type continuous struct{}
+
+func (continuous) Schedule(now, last time.Time, dirty bool) (bool, time.Duration) {
+	return true, 0
+}
+
+// Continuous returns a FramePolicy which draws frames continuously, even when nothing needs to be redrawn.
+// The frame rate is only limited by how long drawing and swapping buffers takes, such as with vsync.
+func Continuous() FramePolicy {
+	return continuous{}
+}
+
+// Deadliner is implemented by widgets with idle work to do at a certain time, such as blinking a text
+// cursor. Event loops use WidgetCollection.NextDeadline to wait for events until the earliest one instead of
+// calling Handle continuously. Widgets which don't implement it are only handled after events.
+type Deadliner interface {
+	// NextDeadline returns when the widget next needs its Handle method called, selected determines if the
+	// widget behaves as selected or not. ok is false if it doesn't need to be.
+	NextDeadline(selected bool) (deadline time.Time, ok bool)
+}
+
+// NextDeadline returns the earliest time a widget in the collection needs WidgetCollection.Handle to be
+// called. ok is false if none of them do.
+func (wc *WidgetCollection) NextDeadline() (deadline time.Time, ok bool) {
+	for _, w := range wc.widgets {
+		d, has := w.(Deadliner)
+		if !has {
+			continue
+		}
+		if t, wants := d.NextDeadline(w.Name() == wc.selected); wants && (!ok || t.Before(deadline)) {
+			deadline, ok = t, true
+		}
+	}
+	return
+}
+
+// UntilDeadline shortens wait, as returned by a FramePolicy, so it ends by the collection's next deadline.
+// The result is never negative unless it's WaitForEvents and the collection has no deadline.
+func (wc *WidgetCollection) UntilDeadline(now time.Time, wait time.Duration) time.Duration {
+	deadline, ok := wc.NextDeadline()
+	if !ok {
+		return wait
+	}
+	until := deadline.Sub(now)
+	if until < 0 {
+		until = 0
+	}
+	if wait < 0 || until < wait {
+		return until
+	}
+	return wait
+}
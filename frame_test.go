@@ -0,0 +1,73 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFramePolicies(t *testing.T) {
+	last := time.Now()
+	for _, c := range []struct {
+		name   string
+		policy FramePolicy
+		since  time.Duration
+		dirty  bool
+		draw   bool
+		wait   time.Duration
+	}{
+		{"OnDemand", OnDemand(), 0, true, true, WaitForEvents},
+		{"OnDemand", OnDemand(), time.Hour, false, false, WaitForEvents},
+		{"MaxFPS", MaxFPS(10), time.Millisecond * 40, true, false, time.Millisecond * 60},
+		{"MaxFPS", MaxFPS(10), time.Millisecond * 100, true, true, WaitForEvents},
+		{"MaxFPS", MaxFPS(10), time.Millisecond * 40, false, false, WaitForEvents},
+		{"MaxFPS", MaxFPS(0), 0, true, true, WaitForEvents},
+		{"Continuous", Continuous(), 0, false, true, 0},
+	} {
+		draw, wait := c.policy.Schedule(last.Add(c.since), last, c.dirty)
+		if draw != c.draw || (!draw && wait != c.wait) {
+			t.Errorf("%s after %v, dirty: %v, returned %v, %v, should be %v, %v.", c.name, c.since, c.dirty,
+				draw, wait, c.draw, c.wait)
+		}
+	}
+}
+
+// deadlineWidget is a dummyWidget which needs handling at deadline while it's selected
+type deadlineWidget struct {
+	*dummyWidget
+	deadline time.Time
+}
+
+func (dw *deadlineWidget) NextDeadline(selected bool) (time.Time, bool) {
+	return dw.deadline, selected
+}
+
+func TestWidgetCollectionNextDeadline(t *testing.T) {
+	now := time.Now()
+	wc, a, b, _ := getOverlappingWidgets(nil)
+	if _, ok := wc.NextDeadline(); ok || wc.UntilDeadline(now, WaitForEvents) != WaitForEvents {
+		t.Error("Widgets which don't implement Deadliner shouldn't have deadlines.")
+	}
+
+	da := &deadlineWidget{a, now.Add(time.Second)}
+	db := &deadlineWidget{b, now.Add(time.Millisecond)}
+	wc.Register(da)
+	wc.Register(db)
+	wc.Select("b")
+	if d, ok := wc.NextDeadline(); !ok || d != db.deadline {
+		t.Errorf("NextDeadline returned %v, %v, should be b's deadline.", d, ok)
+	}
+	wc.Select("a")
+	for _, c := range []struct{ wait, want time.Duration }{
+		{WaitForEvents, time.Second},
+		{time.Millisecond, time.Millisecond},
+		{time.Hour, time.Second},
+	} {
+		if got := wc.UntilDeadline(now, c.wait); got != c.want {
+			t.Errorf("UntilDeadline with %v returned %v, should be %v.", c.wait, got, c.want)
+		}
+	}
+	if got := wc.UntilDeadline(now.Add(time.Hour), WaitForEvents); got != 0 {
+		t.Errorf("UntilDeadline returned %v after the deadline, should be 0.", got)
+	}
+}
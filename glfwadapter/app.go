@@ -0,0 +1,251 @@
+// Copyright (c) 2016, redstarcoder
+package glfwadapter
+
+import (
+	"time"
+
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/llgcode/draw2d"
+	"github.com/redstarcoder/draw2dui"
+)
+
+// App owns a GLFW window and the draw2dui.WidgetCollection drawn in it. It passes the window's events on to
+// the widgets, recreates the GraphicContext when the window is resized, and only draws frames when widgets
+// need to be redrawn, as decided by its draw2dui.FramePolicy. Between frames it waits for events, or until
+// a widget's draw2dui.Deadliner deadline. An App must be created and run on the main thread, locked with
+// runtime.LockOSThread.
+type App struct {
+	// OnKey is called with key events none of the widgets handled, if it isn't nil
+	OnKey func(key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey)
+
+	window        *Window
+	gc            draw2d.GraphicContext
+	widgets       *draw2dui.WidgetCollection
+	policy        draw2dui.FramePolicy
+	width, height int
+	redraw        bool
+	lastFrame     time.Time
+	frames, fps   int       // frames is how many frames were drawn since second, fps the count before it
+	second        time.Time // second is when the current second of counting frames began
+}
+
+// NewApp initializes GLFW and opens a window with the title, width and height. The App draws on demand
+// until SetFramePolicy is used.
+func NewApp(title string, width, height int) (*App, error) {
+	if err := glfw.Init(); err != nil {
+		return nil, err
+	}
+	window, err := glfw.CreateWindow(width, height, title, nil, nil)
+	if err != nil {
+		glfw.Terminate()
+		return nil, err
+	}
+	window.MakeContextCurrent()
+	if err = gl.Init(); err != nil {
+		window.Destroy()
+		glfw.Terminate()
+		return nil, err
+	}
+	glfw.SwapInterval(0)
+
+	a := &App{
+		window: NewWindow(window),
+		gc:     NewGraphicContext(width, height),
+		policy: draw2dui.OnDemand(),
+		redraw: true,
+	}
+	a.widgets = draw2dui.NewWidgetCollection(&a.gc, a.window)
+	a.setViewport(width, height)
+	a.widgets.Reshape(width, height)
+
+	window.SetSizeCallback(a.reshape)
+	window.SetKeyCallback(KeyCallback(a.onKey))
+	window.SetCharCallback(a.onChar)
+	window.SetCursorPosCallback(a.onMMove)
+	window.SetMouseButtonCallback(MouseButtonCallback(a.onMClick))
+	window.SetScrollCallback(a.onScroll)
+	window.SetDropCallback(a.onDrop)
+	window.SetRefreshCallback(a.onRefresh)
+	return a, nil
+}
+
+// GC returns a pointer to the App's draw2d.GraphicContext, which widgets should be created with. The context
+// is replaced whenever the window is resized, keeping its font.
+func (a *App) GC() *draw2d.GraphicContext {
+	return &a.gc
+}
+
+// Window returns the App's window, which widgets should be created with
+func (a *App) Window() *Window {
+	return a.window
+}
+
+// Widgets returns the WidgetCollection drawn in the App's window
+func (a *App) Widgets() *draw2dui.WidgetCollection {
+	return a.widgets
+}
+
+// SetFramePolicy sets the draw2dui.FramePolicy deciding when frames are drawn
+func (a *App) SetFramePolicy(policy draw2dui.FramePolicy) {
+	a.policy = policy
+}
+
+// FPS returns how many frames were drawn during the last second
+func (a *App) FPS() int {
+	a.countFrames(time.Now(), 0)
+	return a.fps
+}
+
+// countFrames adds frames to the frames drawn in the current second, starting a new one when it's over
+func (a *App) countFrames(now time.Time, frames int) {
+	if since := now.Sub(a.second); since >= time.Second {
+		a.fps = a.frames
+		if since >= time.Second*2 {
+			a.fps = 0
+		}
+		a.frames = 0
+		a.second = now
+	}
+	a.frames += frames
+}
+
+// Run processes events and draws frames until the window is closed, then destroys it and terminates GLFW
+func (a *App) Run() {
+	defer glfw.Terminate()
+	defer a.window.window.Destroy()
+	wait := time.Duration(0)
+	for !a.window.window.ShouldClose() {
+		waitEvents(wait)
+		if a.widgets.Handle() {
+			a.redraw = true
+		}
+		var draw bool
+		now := a.widgets.Clock().Now()
+		draw, wait = a.policy.Schedule(now, a.lastFrame, a.redraw || a.widgets.NeedsRedraw())
+		if draw {
+			a.drawFrame()
+			wait = 0
+		}
+		wait = a.widgets.UntilDeadline(now, wait)
+	}
+}
+
+// Close has Run return once it's done processing the current events
+func (a *App) Close() {
+	a.window.window.SetShouldClose(true)
+}
+
+// waitEvents processes events, waiting for up to wait for one, or until one arrives if it's negative
+func waitEvents(wait time.Duration) {
+	switch {
+	case wait == 0:
+		glfw.PollEvents()
+	case wait < 0:
+		glfw.WaitEvents()
+	default:
+		// GLFW 3.1 can't wait with a timeout, so an empty event is posted to stop waiting
+		t := time.AfterFunc(wait, glfw.PostEmptyEvent)
+		glfw.WaitEvents()
+		t.Stop()
+	}
+}
+
+// drawFrame draws the widgets and swaps the window's buffers
+func (a *App) drawFrame() {
+	a.widgets.Draw()
+	gl.Flush()
+	a.window.window.SwapBuffers()
+
+	// Only the damaged parts of the window are drawn, so the new back buffer needs the front buffer's pixels
+	gl.ReadBuffer(gl.FRONT)
+	gl.DrawBuffer(gl.BACK)
+	gl.RasterPos2i(0, int32(a.height))
+	gl.CopyPixels(0, 0, int32(a.width), int32(a.height), gl.COLOR)
+
+	a.redraw = false
+	a.lastFrame = a.widgets.Clock().Now()
+	a.countFrames(time.Now(), 1)
+}
+
+// setViewport maps OpenGL's coordinates to the window's, with the origin at the top left
+func (a *App) setViewport(w, h int) {
+	a.width, a.height = w, h
+	gl.Viewport(0, 0, int32(w), int32(h))
+	gl.MatrixMode(gl.PROJECTION)
+	gl.LoadIdentity()
+	gl.Ortho(0, float64(w), 0, float64(h), -1, 1)
+	// Invert the y axis so increasing y goes down, then shift the origin up to the top left corner
+	gl.Scalef(1, -1, 1)
+	gl.Translatef(0, float32(-h), 0)
+	gl.Enable(gl.BLEND)
+	gl.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	gl.Disable(gl.DEPTH_TEST)
+	a.clear()
+}
+
+// clear fills the window with the theme's background color
+func (a *App) clear() {
+	bg := a.widgets.Theme().Background
+	gl.ClearColor(float32(bg.R)/255, float32(bg.G)/255, float32(bg.B)/255, float32(bg.A)/255)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+}
+
+// reshape is the window's glfw.SizeCallback. It recreates the GraphicContext with the new size.
+func (a *App) reshape(w *glfw.Window, width, height int) {
+	font, size := a.gc.GetFontData(), a.gc.GetFontSize()
+	a.gc = NewGraphicContext(width, height)
+	a.gc.SetFontData(font)
+	a.gc.SetFontSize(size)
+	a.setViewport(width, height)
+	a.widgets.Reshape(width, height)
+	a.redraw = true
+}
+
+func (a *App) onKey(w *glfw.Window, key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey) {
+	_, event := a.widgets.KeyPress(key, action, mods)
+	if event != draw2dui.EventNone {
+		a.redraw = true
+	} else if a.OnKey != nil {
+		a.OnKey(key, action, mods)
+	}
+}
+
+func (a *App) onChar(w *glfw.Window, char rune) {
+	if _, event := a.widgets.CharPress(char); event != draw2dui.EventNone {
+		a.redraw = true
+	}
+}
+
+func (a *App) onMMove(w *glfw.Window, xpos, ypos float64) {
+	if _, event := a.widgets.MMove(xpos, ypos); event != draw2dui.EventNone {
+		a.redraw = true
+	}
+}
+
+func (a *App) onMClick(w *glfw.Window, button draw2dui.MouseButton, action draw2dui.Action, mods draw2dui.ModifierKey) {
+	if _, event := a.widgets.MClick(button, action, mods); event != draw2dui.EventNone {
+		a.redraw = true
+	}
+}
+
+func (a *App) onScroll(w *glfw.Window, xoff, yoff float64) {
+	if _, event := a.widgets.Scroll(xoff, yoff); event != draw2dui.EventNone {
+		a.redraw = true
+	}
+}
+
+// onDrop passes dropped files on to the widget under the mouse. GLFW 3.1 doesn't report drags before the drop,
+// so widgets aren't sent DragOver events.
+func (a *App) onDrop(w *glfw.Window, names []string) {
+	xpos, ypos := w.GetCursorPos()
+	if _, event := a.widgets.Drop(xpos, ypos, names); event != draw2dui.EventNone {
+		a.redraw = true
+	}
+}
+
+func (a *App) onRefresh(w *glfw.Window) {
+	a.clear()
+	a.widgets.Refresh()
+	a.redraw = true
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2016, redstarcoder
+package glfwadapter
+
+import (
+	"math"
+
+	"github.com/go-gl/gl/v2.1/gl"
+	"github.com/llgcode/draw2d/draw2dgl"
+	"github.com/redstarcoder/draw2dui"
+)
+
+// GraphicContext is a draw2dgl.GraphicContext which implements draw2dui.Clipper using the OpenGL scissor
+// test, so a WidgetCollection only redraws the damaged parts of the window.
+type GraphicContext struct {
+	*draw2dgl.GraphicContext
+	height int
+	clips  draw2dui.ClipStack
+}
+
+// NewGraphicContext creates a GraphicContext for a window with the given width and height
+func NewGraphicContext(width, height int) *GraphicContext {
+	return &GraphicContext{
+		GraphicContext: draw2dgl.NewGraphicContext(width, height),
+		height:         height,
+	}
+}
+
+// Save saves the context's state, including its clip rectangle
+func (gc *GraphicContext) Save() {
+	gc.GraphicContext.Save()
+	gc.clips.Push()
+}
+
+// Restore restores the context's state, including its clip rectangle
+func (gc *GraphicContext) Restore() {
+	gc.GraphicContext.Restore()
+	gc.clips.Pop()
+	gc.updateScissor()
+}
+
+// ClipRect restricts drawing to the part of the current clip rectangle inside of x0, y0, x1, y1
+func (gc *GraphicContext) ClipRect(x0, y0, x1, y1 float64) {
+	gc.clips.Intersect(gc.GetMatrixTransform(), x0, y0, x1, y1)
+	gc.updateScissor()
+}
+
+// updateScissor sets OpenGL's scissor box to the current clip rectangle, or disables it if there isn't one.
+// OpenGL's window coordinates start at the bottom left, so y is flipped.
+func (gc *GraphicContext) updateScissor() {
+	r, ok := gc.clips.Clip()
+	if !ok {
+		gl.Disable(gl.SCISSOR_TEST)
+		return
+	}
+	x0, y0 := int32(math.Floor(r.X0)), int32(math.Floor(r.Y0))
+	x1, y1 := int32(math.Ceil(r.X1)), int32(math.Ceil(r.Y1))
+	if x1 < x0 {
+		x1 = x0
+	}
+	if y1 < y0 {
+		y1 = y0
+	}
+	gl.Enable(gl.SCISSOR_TEST)
+	gl.Scissor(x0, int32(gc.height)-y1, x1-x0, y1-y0)
+}
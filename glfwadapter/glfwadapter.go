@@ -0,0 +1,119 @@
+// Copyright (c) 2016, redstarcoder
+// Package glfwadapter translates GLFW windows and callbacks into draw2dui's toolkit-neutral types.
+package glfwadapter
+
+import (
+	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/redstarcoder/draw2dui"
+)
+
+var standardCursors = map[draw2dui.CursorShape]int{
+	draw2dui.ArrowCursor:     int(glfw.ArrowCursor),
+	draw2dui.IBeamCursor:     int(glfw.IBeamCursor),
+	draw2dui.CrosshairCursor: int(glfw.CrosshairCursor),
+	draw2dui.HandCursor:      int(glfw.HandCursor),
+	draw2dui.HResizeCursor:   int(glfw.HResizeCursor),
+	draw2dui.VResizeCursor:   int(glfw.VResizeCursor),
+}
+
+// Window wraps a *glfw.Window so it can be used as a draw2dui.CursorSetter, draw2dui.Waker and
+// draw2dui.Clipboard. Apart from Wake, it must only be used from the thread GLFW runs on.
+type Window struct {
+	window  *glfw.Window
+	cursors map[draw2dui.CursorShape]*glfw.Cursor
+}
+
+// NewWindow wraps window
+func NewWindow(window *glfw.Window) *Window {
+	return &Window{
+		window:  window,
+		cursors: make(map[draw2dui.CursorShape]*glfw.Cursor, len(standardCursors)),
+	}
+}
+
+// GLFW returns the wrapped *glfw.Window
+func (w *Window) GLFW() *glfw.Window {
+	return w.window
+}
+
+// Wake wakes up glfw.WaitEvents by posting an empty event. It's thread-safe, so draw2dui.WidgetCollection.Post
+// uses it to have the event loop run functions posted from other goroutines.
+func (w *Window) Wake() {
+	glfw.PostEmptyEvent()
+}
+
+// ClipboardString returns the system clipboard's text, or "" if it doesn't hold any
+func (w *Window) ClipboardString() string {
+	s, err := w.window.GetClipboardString()
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// SetClipboardString replaces the system clipboard's text with s
+func (w *Window) SetClipboardString(s string) {
+	w.window.SetClipboardString(s)
+}
+
+// SetCursor changes the mouse cursor to shape. Cursors are created once and reused.
+func (w *Window) SetCursor(shape draw2dui.CursorShape) {
+	c, ok := w.cursors[shape]
+	if !ok {
+		c = glfw.CreateStandardCursor(standardCursors[shape])
+		w.cursors[shape] = c
+	}
+	w.window.SetCursor(c)
+}
+
+// Key translates a glfw.Key. draw2dui's keys share GLFW's values.
+func Key(key glfw.Key) draw2dui.Key {
+	return draw2dui.Key(key)
+}
+
+// Action translates a glfw.Action
+func Action(action glfw.Action) draw2dui.Action {
+	switch action {
+	case glfw.Press:
+		return draw2dui.Press
+	case glfw.Repeat:
+		return draw2dui.Repeat
+	}
+	return draw2dui.Release
+}
+
+// Mods translates a glfw.ModifierKey
+func Mods(mods glfw.ModifierKey) (m draw2dui.ModifierKey) {
+	if mods&glfw.ModShift != 0 {
+		m |= draw2dui.ModShift
+	}
+	if mods&glfw.ModControl != 0 {
+		m |= draw2dui.ModControl
+	}
+	if mods&glfw.ModAlt != 0 {
+		m |= draw2dui.ModAlt
+	}
+	if mods&glfw.ModSuper != 0 {
+		m |= draw2dui.ModSuper
+	}
+	return
+}
+
+// MouseButton translates a glfw.MouseButton
+func MouseButton(button glfw.MouseButton) draw2dui.MouseButton {
+	return draw2dui.MouseButton(button)
+}
+
+// KeyCallback returns a glfw.KeyCallback which translates its arguments and passes them to f
+func KeyCallback(f func(w *glfw.Window, key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey)) glfw.KeyCallback {
+	return func(w *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
+		f(w, Key(key), Action(action), Mods(mods))
+	}
+}
+
+// MouseButtonCallback returns a glfw.MouseButtonCallback which translates its arguments and passes them to f
+func MouseButtonCallback(f func(w *glfw.Window, button draw2dui.MouseButton, action draw2dui.Action, mods draw2dui.ModifierKey)) glfw.MouseButtonCallback {
+	return func(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) {
+		f(w, MouseButton(button), Action(action), Mods(mods))
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2016, redstarcoder
+package glfwadapter
+
+import (
+	"testing"
+
+	"github.com/go-gl/glfw/v3.1/glfw"
+	"github.com/redstarcoder/draw2dui"
+)
+
+func TestTranslate(t *testing.T) {
+	if Key(glfw.KeyEnter) != draw2dui.KeyEnter || Key(glfw.KeyA) != draw2dui.KeyA {
+		t.Error("Keys should share GLFW's values.")
+	}
+	if Action(glfw.Press) != draw2dui.Press || Action(glfw.Repeat) != draw2dui.Repeat ||
+		Action(glfw.Release) != draw2dui.Release {
+		t.Error("Actions weren't translated.")
+	}
+	if Mods(glfw.ModShift|glfw.ModAlt) != draw2dui.ModShift|draw2dui.ModAlt {
+		t.Error("Mods weren't translated.")
+	}
+	if MouseButton(glfw.MouseButtonRight) != draw2dui.MouseButtonRight {
+		t.Error("MouseButtons weren't translated.")
+	}
+}
+
+func TestKeyCallback(t *testing.T) {
+	var gotKey draw2dui.Key
+	var gotMods draw2dui.ModifierKey
+	cb := KeyCallback(func(w *glfw.Window, key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey) {
+		gotKey, gotMods = key, mods
+	})
+	cb(nil, glfw.KeyTab, 0, glfw.Press, glfw.ModShift)
+	if gotKey != draw2dui.KeyTab || gotMods != draw2dui.ModShift {
+		t.Fail()
+	}
+}
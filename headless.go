@@ -0,0 +1,95 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/golang/freetype/raster"
+	"github.com/llgcode/draw2d/draw2dimg"
+)
+
+// ImageContext is a draw2dimg.GraphicContext which draws into an in-memory image. Using it with a nil window
+// lets a WidgetCollection and its widgets render without making any OpenGL or GLFW calls, which is useful for
+// tests and for taking screenshots on machines without a GPU. It implements Clipper.
+type ImageContext struct {
+	*draw2dimg.GraphicContext
+	// Image is the image being drawn to
+	Image   *image.RGBA
+	painter *clipPainter
+	clips   ClipStack
+}
+
+// NewImageContext creates an ImageContext drawing into a new white image with the given width and height.
+func NewImageContext(width, height int) *ImageContext {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.ZP, draw.Src)
+	painter := &clipPainter{RGBAPainter: raster.NewRGBAPainter(img), clip: img.Bounds()}
+	return &ImageContext{
+		GraphicContext: draw2dimg.NewGraphicContextWithPainter(img, painter),
+		Image:          img,
+		painter:        painter,
+	}
+}
+
+// Save saves the context's state, including its clip rectangle
+func (ic *ImageContext) Save() {
+	ic.GraphicContext.Save()
+	ic.clips.Push()
+}
+
+// Restore restores the context's state, including its clip rectangle
+func (ic *ImageContext) Restore() {
+	ic.GraphicContext.Restore()
+	ic.clips.Pop()
+	ic.updateClip()
+}
+
+// ClipRect restricts drawing to the part of the current clip rectangle inside of x0, y0, x1, y1
+func (ic *ImageContext) ClipRect(x0, y0, x1, y1 float64) {
+	ic.clips.Intersect(ic.GetMatrixTransform(), x0, y0, x1, y1)
+	ic.updateClip()
+}
+
+// updateClip passes the current clip rectangle on to the painter
+func (ic *ImageContext) updateClip() {
+	clip := ic.Image.Bounds()
+	if r, ok := ic.clips.Clip(); ok {
+		clip = clip.Intersect(image.Rect(int(math.Floor(r.X0)), int(math.Floor(r.Y0)),
+			int(math.Ceil(r.X1)), int(math.Ceil(r.Y1))))
+	}
+	ic.painter.clip = clip
+}
+
+// clipPainter is a raster.RGBAPainter which only paints inside of its clip rectangle
+type clipPainter struct {
+	*raster.RGBAPainter
+	clip  image.Rectangle
+	spans []raster.Span
+}
+
+// Paint paints the parts of ss inside of p's clip rectangle
+func (p *clipPainter) Paint(ss []raster.Span, done bool) {
+	p.spans = p.spans[:0]
+	for _, s := range ss {
+		if s.Y < p.clip.Min.Y || s.Y >= p.clip.Max.Y {
+			continue
+		}
+		if s.X0 < p.clip.Min.X {
+			s.X0 = p.clip.Min.X
+		}
+		if s.X1 > p.clip.Max.X {
+			s.X1 = p.clip.Max.X
+		}
+		if s.X0 < s.X1 {
+			p.spans = append(p.spans, s)
+		}
+	}
+	p.RGBAPainter.Paint(p.spans, done)
+}
+
+// SaveToPngFile saves everything drawn so far to a PNG file at path
+func (ic *ImageContext) SaveToPngFile(path string) error {
+	return draw2dimg.SaveToPngFile(path, ic.Image)
+}
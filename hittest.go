@@ -0,0 +1,103 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import (
+	"math"
+
+	"github.com/llgcode/draw2d"
+)
+
+// curveSegments is how many line segments a curve is flattened into for hit-testing
+const curveSegments = 16
+
+// IsPointInPath checks if point x, y is inside of path, using the even-odd rule like draw2d does when filling.
+// Points within half a pixel of the path's outline are considered inside too, since widgets stroke their
+// shapes. It's pure Go, so unlike draw2dglkit.IsPointInShape it doesn't need an offscreen window.
+func IsPointInPath(path *draw2d.Path, x, y float64) bool {
+	inside := false
+	onEdge := false
+	edge := func(x1, y1, x2, y2 float64) {
+		if (y1 > y) != (y2 > y) && x < x1+(y-y1)*(x2-x1)/(y2-y1) {
+			inside = !inside
+		}
+		if !onEdge && distToSegment(x, y, x1, y1, x2, y2) <= 0.5 {
+			onEdge = true
+		}
+	}
+
+	// Subpaths are closed implicitly when filling, so each one gets a closing edge
+	var startX, startY, lastX, lastY float64
+	hasSubpath := false
+	closeSubpath := func() {
+		if hasSubpath {
+			edge(lastX, lastY, startX, startY)
+			lastX, lastY = startX, startY
+		}
+	}
+	j := 0
+	for _, cmp := range path.Components {
+		switch cmp {
+		case draw2d.MoveToCmp:
+			closeSubpath()
+			hasSubpath = true
+			startX, startY = path.Points[j], path.Points[j+1]
+			lastX, lastY = startX, startY
+			j += 2
+		case draw2d.LineToCmp:
+			edge(lastX, lastY, path.Points[j], path.Points[j+1])
+			lastX, lastY = path.Points[j], path.Points[j+1]
+			j += 2
+		case draw2d.QuadCurveToCmp:
+			x0, y0 := lastX, lastY
+			cx, cy, ex, ey := path.Points[j], path.Points[j+1], path.Points[j+2], path.Points[j+3]
+			for i := 1; i <= curveSegments; i++ {
+				t := float64(i) / curveSegments
+				mt := 1 - t
+				px := mt*mt*x0 + 2*mt*t*cx + t*t*ex
+				py := mt*mt*y0 + 2*mt*t*cy + t*t*ey
+				edge(lastX, lastY, px, py)
+				lastX, lastY = px, py
+			}
+			j += 4
+		case draw2d.CubicCurveToCmp:
+			x0, y0 := lastX, lastY
+			c1x, c1y := path.Points[j], path.Points[j+1]
+			c2x, c2y := path.Points[j+2], path.Points[j+3]
+			ex, ey := path.Points[j+4], path.Points[j+5]
+			for i := 1; i <= curveSegments; i++ {
+				t := float64(i) / curveSegments
+				mt := 1 - t
+				px := mt*mt*mt*x0 + 3*mt*mt*t*c1x + 3*mt*t*t*c2x + t*t*t*ex
+				py := mt*mt*mt*y0 + 3*mt*mt*t*c1y + 3*mt*t*t*c2y + t*t*t*ey
+				edge(lastX, lastY, px, py)
+				lastX, lastY = px, py
+			}
+			j += 6
+		case draw2d.ArcToCmp:
+			cx, cy, rx, ry := path.Points[j], path.Points[j+1], path.Points[j+2], path.Points[j+3]
+			start, angle := path.Points[j+4], path.Points[j+5]
+			n := int(math.Ceil(math.Abs(angle) / (math.Pi / 2) * curveSegments))
+			for i := 1; i <= n; i++ {
+				a := start + angle*float64(i)/float64(n)
+				px, py := cx+math.Cos(a)*rx, cy+math.Sin(a)*ry
+				edge(lastX, lastY, px, py)
+				lastX, lastY = px, py
+			}
+			j += 6
+		case draw2d.CloseCmp:
+			closeSubpath()
+		}
+	}
+	closeSubpath()
+	return inside || onEdge
+}
+
+// distToSegment returns the distance between point x, y and the line segment x1, y1 to x2, y2
+func distToSegment(x, y, x1, y1, x2, y2 float64) float64 {
+	dx, dy := x2-x1, y2-y1
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((x-x1)*dx+(y-y1)*dy)/l))
+	}
+	return math.Hypot(x-(x1+t*dx), y-(y1+t*dy))
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import (
+	"testing"
+
+	"github.com/llgcode/draw2d"
+	"github.com/llgcode/draw2d/draw2dkit"
+)
+
+type hitTest struct {
+	x, y   float64
+	inside bool
+}
+
+func checkHits(t *testing.T, name string, path *draw2d.Path, tests []hitTest) {
+	for _, test := range tests {
+		if IsPointInPath(path, test.x, test.y) != test.inside {
+			t.Errorf("%s: IsPointInPath(%v, %v) should be %v.", name, test.x, test.y, test.inside)
+		}
+	}
+}
+
+func TestIsPointInPathRectangle(t *testing.T) {
+	path := &draw2d.Path{}
+	draw2dkit.Rectangle(path, 10, 10, 59, 29)
+	checkHits(t, "Rectangle", path, []hitTest{
+		{30, 20, true},
+		{10, 10, true},
+		{59, 29, true},
+		{9.6, 20, true},
+		{9, 20, false},
+		{61, 20, false},
+		{30, 31, false},
+		{0, 0, false},
+	})
+}
+
+func TestIsPointInPathRoundedRectangle(t *testing.T) {
+	path := &draw2d.Path{}
+	draw2dkit.RoundedRectangle(path, 0, 0, 100, 100, 40, 40)
+	checkHits(t, "RoundedRectangle", path, []hitTest{
+		{50, 50, true},
+		{50, 1, true},
+		{1, 50, true},
+		{2, 2, false},
+		{98, 98, false},
+	})
+}
+
+func TestIsPointInPathCurves(t *testing.T) {
+	path := &draw2d.Path{}
+	draw2dkit.Circle(path, 50, 50, 20)
+	checkHits(t, "Circle", path, []hitTest{
+		{50, 50, true},
+		{65, 50, true},
+		{50, 71, false},
+		{65, 65, false},
+	})
+
+	path = &draw2d.Path{}
+	path.MoveTo(0, 0)
+	path.CubicCurveTo(0, 100, 100, 100, 100, 0)
+	path.Close()
+	checkHits(t, "CubicCurveTo", path, []hitTest{
+		{50, 50, true},
+		{50, 80, false},
+		{50, -5, false},
+	})
+
+	path = &draw2d.Path{}
+	path.MoveTo(0, 0)
+	path.QuadCurveTo(50, 100, 100, 0)
+	checkHits(t, "QuadCurveTo", path, []hitTest{
+		{50, 25, true},
+		{50, 60, false},
+	})
+}
+
+func TestIsPointInPathEvenOdd(t *testing.T) {
+	path := &draw2d.Path{}
+	draw2dkit.Rectangle(path, 0, 0, 100, 100)
+	draw2dkit.Rectangle(path, 25, 25, 75, 75)
+	checkHits(t, "EvenOdd", path, []hitTest{
+		{10, 10, true},
+		{50, 50, false},
+		{25, 50, true},
+	})
+}
@@ -0,0 +1,318 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import (
+	"fmt"
+	"math"
+)
+
+// Layout positions and sizes widgets, and other Layouts, inside of a rectangle. Setting one with
+// WidgetCollection.SetLayout has it rearranged whenever the collection is reshaped.
+type Layout interface {
+	// Arrange positions and sizes everything in the layout to fit inside of x, y, width, height
+	Arrange(x, y, width, height float64)
+	// Size returns the smallest width and height the layout fits in
+	Size() (width, height float64)
+}
+
+// Alignment determines where a widget is placed inside of space larger than it
+type Alignment int
+
+// Alignments
+const (
+	AlignStart  Alignment = iota // AlignStart places widgets at the left or top
+	AlignCenter                  // AlignCenter centers widgets
+	AlignEnd                     // AlignEnd places widgets at the right or bottom
+	AlignFill                    // AlignFill stretches widgets to fill the space
+)
+
+// align returns the offset and length of something with the natural length size placed inside of space
+func (a Alignment) align(size, space float64) (offset, length float64) {
+	if size > space {
+		size = space
+	}
+	switch a {
+	case AlignCenter:
+		return (space - size) / 2, size
+	case AlignEnd:
+		return space - size, size
+	case AlignFill:
+		return 0, space
+	}
+	return 0, size
+}
+
+// Sizer is implemented by widgets which size themselves to their content, such as buttons and labels.
+// Layouts ask for their natural size whenever they're arranged, so they follow changes to the content and
+// Theme. Widgets which don't implement it are never made smaller than they were when added to a Layout.
+type Sizer interface {
+	// NaturalSize returns the width and height the widget needs to fit its content
+	NaturalSize() (width, height float64)
+}
+
+// layoutItem is a widget or Layout held by a Layout
+type layoutItem struct {
+	widget        Widget
+	layout        Layout
+	width, height float64 // width and height are the widget's dimensions when it was added
+	stretch       float64
+}
+
+func newLayoutItem(w Widget, l Layout, stretch float64) layoutItem {
+	item := layoutItem{widget: w, layout: l, stretch: stretch}
+	if w != nil {
+		item.width, item.height = w.GetDimensions()
+	}
+	return item
+}
+
+// size returns the item's natural width and height
+func (it *layoutItem) size() (float64, float64) {
+	if it.layout != nil {
+		return it.layout.Size()
+	}
+	if s, ok := it.widget.(Sizer); ok {
+		return s.NaturalSize()
+	}
+	return it.width, it.height
+}
+
+// place positions and sizes the item
+func (it *layoutItem) place(x, y, width, height float64) {
+	if it.layout != nil {
+		it.layout.Arrange(x, y, width, height)
+		return
+	}
+	it.widget.SetPos(x, y)
+	it.widget.SetDimensions(width, height)
+}
+
+// distribute shares extra space between lengths according to their stretch factors, returning the new
+// lengths. Nothing is shared if extra is negative or no length stretches.
+func distribute(lengths, stretch []float64, extra float64) []float64 {
+	total := 0.
+	for _, s := range stretch {
+		total += s
+	}
+	out := make([]float64, len(lengths))
+	for i, l := range lengths {
+		out[i] = l
+		if extra > 0 && total > 0 {
+			out[i] += extra * stretch[i] / total
+		}
+	}
+	return out
+}
+
+// Box is a Layout which places widgets in a single row or column, in the order they were added. Space left
+// over along the row or column is shared between items according to their stretch factors.
+type Box struct {
+	// Vertical determines if the box is a column instead of a row
+	Vertical bool
+	// Spacing is the space left between items
+	Spacing float64
+	// Padding is the space left around the edges of the box
+	Padding float64
+	// Align determines how items are placed across the box, horizontally in a column and vertically in a row
+	Align Alignment
+	items []layoutItem
+}
+
+// NewVBox creates a Box which places widgets from the top to the bottom
+func NewVBox(spacing, padding float64) *Box {
+	return &Box{Vertical: true, Spacing: spacing, Padding: padding, Align: AlignFill}
+}
+
+// NewHBox creates a Box which places widgets from the left to the right
+func NewHBox(spacing, padding float64) *Box {
+	return &Box{Spacing: spacing, Padding: padding, Align: AlignStart}
+}
+
+// Add adds w to the end of the box. Its natural size if it's a Sizer, otherwise its current dimensions, are
+// the smallest it will be made. stretch is its share of any left over space, 0 keeps it at its smallest.
+func (b *Box) Add(w Widget, stretch float64) *Box {
+	b.items = append(b.items, newLayoutItem(w, nil, stretch))
+	return b
+}
+
+// AddLayout adds l to the end of the box, stretch is its share of any left over space
+func (b *Box) AddLayout(l Layout, stretch float64) *Box {
+	b.items = append(b.items, newLayoutItem(nil, l, stretch))
+	return b
+}
+
+// axes returns width and height as lengths along and across b
+func (b *Box) axes(width, height float64) (along, across float64) {
+	if b.Vertical {
+		return height, width
+	}
+	return width, height
+}
+
+// Size returns the smallest width and height b fits in
+func (b *Box) Size() (width, height float64) {
+	var along, across float64
+	for i := range b.items {
+		l, c := b.axes(b.items[i].size())
+		along += l
+		across = math.Max(across, c)
+	}
+	if len(b.items) > 1 {
+		along += b.Spacing * float64(len(b.items)-1)
+	}
+	return b.axes(along+b.Padding*2, across+b.Padding*2)
+}
+
+// Arrange positions and sizes b's items to fit inside of x, y, width, height
+func (b *Box) Arrange(x, y, width, height float64) {
+	x, y = x+b.Padding, y+b.Padding
+	along, across := b.axes(width-b.Padding*2, height-b.Padding*2)
+
+	lengths := make([]float64, len(b.items))
+	stretch := make([]float64, len(b.items))
+	extra := along - b.Spacing*float64(len(b.items)-1)
+	for i := range b.items {
+		lengths[i], _ = b.axes(b.items[i].size())
+		stretch[i] = b.items[i].stretch
+		extra -= lengths[i]
+	}
+	lengths = distribute(lengths, stretch, extra)
+
+	pos := 0.
+	for i := range b.items {
+		_, c := b.axes(b.items[i].size())
+		offset, c := b.Align.align(c, across)
+		if b.Vertical {
+			b.items[i].place(x+offset, y+pos, c, lengths[i])
+		} else {
+			b.items[i].place(x+pos, y+offset, lengths[i], c)
+		}
+		pos += lengths[i] + b.Spacing
+	}
+}
+
+// gridItem is a layoutItem in a Grid cell
+type gridItem struct {
+	layoutItem
+	row, col int
+}
+
+// Grid is a Layout which places widgets in cells of a grid. Each column is as wide as its widest item and
+// each row as tall as its tallest, left over space is shared between columns and rows according to their
+// stretch factors.
+type Grid struct {
+	// Spacing is the space left between columns and rows
+	Spacing float64
+	// Padding is the space left around the edges of the grid
+	Padding float64
+	// HAlign and VAlign determine how items are placed inside of their cells
+	HAlign, VAlign         Alignment
+	items                  []gridItem
+	colStretch, rowStretch map[int]float64
+}
+
+// NewGrid creates an empty Grid
+func NewGrid(spacing, padding float64) *Grid {
+	return &Grid{Spacing: spacing, Padding: padding, HAlign: AlignFill, VAlign: AlignCenter,
+		colStretch: make(map[int]float64), rowStretch: make(map[int]float64)}
+}
+
+// Add places w in the cell at row, col. Its natural size if it's a Sizer, otherwise its current dimensions,
+// are the smallest it will be made. Add panics if row or col is negative.
+func (g *Grid) Add(w Widget, row, col int) *Grid {
+	checkCell(row, col)
+	g.items = append(g.items, gridItem{newLayoutItem(w, nil, 0), row, col})
+	return g
+}
+
+// AddLayout places l in the cell at row, col. AddLayout panics if row or col is negative.
+func (g *Grid) AddLayout(l Layout, row, col int) *Grid {
+	checkCell(row, col)
+	g.items = append(g.items, gridItem{newLayoutItem(nil, l, 0), row, col})
+	return g
+}
+
+// checkCell panics if row or col is negative
+func checkCell(row, col int) {
+	if row < 0 || col < 0 {
+		panic(fmt.Sprintf("draw2dui: grid cell %d, %d is negative", row, col))
+	}
+}
+
+// SetColumnStretch sets col's share of any left over width
+func (g *Grid) SetColumnStretch(col int, stretch float64) {
+	g.colStretch[col] = stretch
+}
+
+// SetRowStretch sets row's share of any left over height
+func (g *Grid) SetRowStretch(row int, stretch float64) {
+	g.rowStretch[row] = stretch
+}
+
+// cells returns the natural widths of g's columns and heights of its rows
+func (g *Grid) cells() (widths, heights []float64) {
+	for i := range g.items {
+		it := &g.items[i]
+		for len(widths) <= it.col {
+			widths = append(widths, 0)
+		}
+		for len(heights) <= it.row {
+			heights = append(heights, 0)
+		}
+		w, h := it.size()
+		widths[it.col] = math.Max(widths[it.col], w)
+		heights[it.row] = math.Max(heights[it.row], h)
+	}
+	return
+}
+
+// span returns the total of lengths with spacing between them, and padding around them
+func (g *Grid) span(lengths []float64) float64 {
+	total := g.Padding * 2
+	for _, l := range lengths {
+		total += l
+	}
+	if len(lengths) > 1 {
+		total += g.Spacing * float64(len(lengths)-1)
+	}
+	return total
+}
+
+// Size returns the smallest width and height g fits in
+func (g *Grid) Size() (width, height float64) {
+	widths, heights := g.cells()
+	return g.span(widths), g.span(heights)
+}
+
+// Arrange positions and sizes g's items to fit inside of x, y, width, height
+func (g *Grid) Arrange(x, y, width, height float64) {
+	widths, heights := g.cells()
+	stretch := func(lengths []float64, factors map[int]float64) []float64 {
+		s := make([]float64, len(lengths))
+		for i := range s {
+			s[i] = factors[i]
+		}
+		return s
+	}
+	widths = distribute(widths, stretch(widths, g.colStretch), width-g.span(widths))
+	heights = distribute(heights, stretch(heights, g.rowStretch), height-g.span(heights))
+
+	// offsets returns where each column or row starts
+	offsets := func(start float64, lengths []float64) []float64 {
+		out := make([]float64, len(lengths))
+		start += g.Padding
+		for i, l := range lengths {
+			out[i] = start
+			start += l + g.Spacing
+		}
+		return out
+	}
+	xs, ys := offsets(x, widths), offsets(y, heights)
+	for i := range g.items {
+		it := &g.items[i]
+		w, h := it.size()
+		ox, w := g.HAlign.align(w, widths[it.col])
+		oy, h := g.VAlign.align(h, heights[it.row])
+		it.place(xs[it.col]+ox, ys[it.row]+oy, w, h)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import "testing"
+
+// checkRect checks w's position and dimensions
+func checkRect(t *testing.T, w Widget, x, y, width, height float64) {
+	gx, gy := w.GetPos()
+	gw, gh := w.GetDimensions()
+	if gx != x || gy != y || gw != width || gh != height {
+		t.Errorf("%s is at %v, %v, %v, %v, should be at %v, %v, %v, %v.", w.Name(), gx, gy, gw, gh,
+			x, y, width, height)
+	}
+}
+
+func TestVBox(t *testing.T) {
+	a := newDummyWidget("a", 0, 0, 50, 20, nil)
+	b := newDummyWidget("b", 0, 0, 80, 20, nil)
+	c := newDummyWidget("c", 0, 0, 30, 10, nil)
+	box := NewVBox(5, 10).Add(a, 0).Add(b, 1).Add(c, 2)
+	if w, h := box.Size(); w != 100 || h != 80 {
+		t.Errorf("Size is %v, %v, should be 100, 80.", w, h)
+	}
+
+	box.Arrange(0, 0, 200, 170)
+	checkRect(t, a, 10, 10, 180, 20)
+	checkRect(t, b, 10, 35, 180, 50)
+	checkRect(t, c, 10, 90, 180, 70)
+
+	box.Align = AlignCenter
+	box.Arrange(0, 0, 200, 80)
+	checkRect(t, a, 75, 10, 50, 20)
+	checkRect(t, b, 60, 35, 80, 20)
+	checkRect(t, c, 85, 60, 30, 10)
+}
+
+func TestHBox(t *testing.T) {
+	a := newDummyWidget("a", 0, 0, 50, 20, nil)
+	b := newDummyWidget("b", 0, 0, 50, 40, nil)
+	inner := NewVBox(0, 0).Add(newDummyWidget("c", 0, 0, 10, 10, nil), 0)
+	box := NewHBox(10, 0).Add(a, 1).AddLayout(inner, 1).Add(b, 0)
+	box.Align = AlignEnd
+	box.Arrange(100, 100, 150, 40)
+	checkRect(t, a, 100, 120, 60, 20)
+	checkRect(t, b, 200, 100, 50, 40)
+	checkRect(t, inner.items[0].widget, 170, 130, 20, 10)
+}
+
+func TestGrid(t *testing.T) {
+	grid := NewGrid(10, 5)
+	label := newDummyWidget("label", 0, 0, 40, 10, nil)
+	field := newDummyWidget("field", 0, 0, 100, 20, nil)
+	button := newDummyWidget("button", 0, 0, 30, 20, nil)
+	grid.Add(label, 0, 0).Add(field, 0, 1).Add(button, 1, 1)
+	grid.SetColumnStretch(1, 1)
+	if w, h := grid.Size(); w != 160 || h != 60 {
+		t.Errorf("Size is %v, %v, should be %v, %v.", w, h, 160, 60)
+	}
+
+	grid.Arrange(0, 0, 260, 100)
+	checkRect(t, label, 5, 10, 40, 10)
+	checkRect(t, field, 55, 5, 200, 20)
+	checkRect(t, button, 55, 35, 200, 20)
+}
+
+func TestWidgetCollectionLayout(t *testing.T) {
+	wc, a, b, c := getOverlappingWidgets(nil)
+	wc.SetLayout(NewHBox(0, 0).Add(a, 1).Add(b, 1).Add(c, 1))
+	checkRect(t, a, 0, 0, 100, 100)
+
+	wc.Reshape(600, 300)
+	checkRect(t, a, 0, 0, 200, 100)
+	checkRect(t, c, 400, 0, 200, 100)
+	wc.Draw()
+	wc.Reshape(300, 300)
+	checkRect(t, c, 200, 0, 100, 100)
+}
+
+func TestGridNegativeCell(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Adding to a negative cell should panic.")
+		}
+	}()
+	NewGrid(0, 0).Add(newDummyWidget("a", 0, 0, 10, 10, nil), 0, -1)
+}
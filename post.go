@@ -0,0 +1,43 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+// Waker is implemented by windows whose event loop can be woken up from any goroutine, such as one waiting
+// for events. See the glfwadapter package for a GLFW implementation.
+type Waker interface {
+	// Wake wakes the event loop up, it must be thread-safe
+	Wake()
+}
+
+// Post queues f to be run on the thread calling Handle, during its next call, and wakes the event loop with
+// the collection's Waker. Widgets must only be used from that thread, so goroutines should Post anything
+// touching them. Post is thread-safe, and functions run in the order they were posted.
+func (wc *WidgetCollection) Post(f func()) {
+	wc.postLock.Lock()
+	wc.posted = append(wc.posted, f)
+	waker := wc.waker
+	wc.postLock.Unlock()
+	if waker != nil {
+		waker.Wake()
+	}
+}
+
+// SetWaker sets the Waker Post wakes the event loop with, replacing the collection's window if it
+// implemented Waker. It may be nil. SetWaker is thread-safe.
+func (wc *WidgetCollection) SetWaker(waker Waker) {
+	wc.postLock.Lock()
+	wc.waker = waker
+	wc.postLock.Unlock()
+}
+
+// runPosted runs the functions queued by Post, returning whether there were any. Functions posted while
+// they're running are left for the next call.
+func (wc *WidgetCollection) runPosted() bool {
+	wc.postLock.Lock()
+	posted := wc.posted
+	wc.posted = nil
+	wc.postLock.Unlock()
+	for _, f := range posted {
+		f()
+	}
+	return len(posted) > 0
+}
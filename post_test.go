@@ -0,0 +1,58 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingWaker counts how many times it's woken
+type countingWaker struct {
+	dummyWindow
+	wakes int32
+}
+
+func (cw *countingWaker) Wake() { atomic.AddInt32(&cw.wakes, 1) }
+
+func TestWidgetCollectionPost(t *testing.T) {
+	waker := &countingWaker{}
+	wc := NewWidgetCollection(nil, waker)
+	var ran []int
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wc.Post(func() { ran = append(ran, len(ran)) })
+		}()
+	}
+	wg.Wait()
+	if len(ran) != 0 {
+		t.Error("Posted functions should wait for Handle.")
+	}
+	if atomic.LoadInt32(&waker.wakes) != 10 {
+		t.Errorf("The window was woken %d times, should be 10.", waker.wakes)
+	}
+	if !wc.Handle() || len(ran) != 10 {
+		t.Errorf("Handle ran %d posted functions and should request a redraw.", len(ran))
+	}
+	if wc.Handle() {
+		t.Error("Handle shouldn't request a redraw without posted functions.")
+	}
+
+	var order []string
+	wc.SetWaker(nil)
+	wc.Post(func() {
+		order = append(order, "a")
+		wc.Post(func() { order = append(order, "c") })
+	})
+	wc.Post(func() { order = append(order, "b") })
+	wc.Handle()
+	checkOrder(t, order, "a", "b")
+	wc.Handle()
+	checkOrder(t, order, "a", "b", "c")
+	if waker.wakes != 10 {
+		t.Error("SetWaker should replace the window.")
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import (
+	"image/color"
+
+	"github.com/llgcode/draw2d"
+)
+
+// Theme holds the colors, font and metrics widgets are drawn with. Setting one with
+// WidgetCollection.SetTheme restyles every widget in the collection.
+type Theme struct {
+	// Foreground is the color of text
+	Foreground color.RGBA
+	// Background is the color behind widgets and their text
+	Background color.RGBA
+	// Border is the color of widgets' borders
+	Border color.RGBA
+	// HoverForeground and HoverBackground replace Foreground and Background for widgets under the mouse,
+	// such as buttons
+	HoverForeground, HoverBackground color.RGBA
+	// Focus is the color of the selected widget's border
+	Focus color.RGBA
+	// Disabled is the color of disabled widgets' text and borders
+	Disabled color.RGBA
+	// Selection is the color behind selected text
+	Selection color.RGBA
+
+	// Font is the font text is drawn with. If its Name is empty, the draw2d.GraphicContext's font is used.
+	Font draw2d.FontData
+	// FontSize is the size text is drawn with. If it's 0, the draw2d.GraphicContext's font size is used.
+	FontSize float64
+
+	// BorderWidth is the width of widgets' borders
+	BorderWidth float64
+	// TextPadding is the space between the border of text widgets and their text
+	TextPadding float64
+	// ButtonPadding is the space between the border of buttons and their text
+	ButtonPadding float64
+	// LineSpacing is the space above each line of text
+	LineSpacing float64
+}
+
+// DefaultTheme returns a new copy of the default theme, black on white
+func DefaultTheme() *Theme {
+	return &Theme{
+		Foreground:      color.RGBA{0, 0, 0, 0xff},
+		Background:      color.RGBA{255, 255, 255, 0xff},
+		Border:          color.RGBA{0, 0, 0, 0xff},
+		HoverForeground: color.RGBA{255, 255, 255, 0xff},
+		HoverBackground: color.RGBA{0, 0, 0, 0xff},
+		Focus:           color.RGBA{0x33, 0x66, 0xcc, 0xff},
+		Disabled:        color.RGBA{0x99, 0x99, 0x99, 0xff},
+		Selection:       color.RGBA{0xb4, 0xd5, 0xfe, 0xff},
+		BorderWidth:     1,
+		TextPadding:     1,
+		ButtonPadding:   3,
+		LineSpacing:     3,
+	}
+}
+
+// DarkTheme returns a new copy of a dark theme, light grey on dark grey
+func DarkTheme() *Theme {
+	return &Theme{
+		Foreground:      color.RGBA{0xdd, 0xdd, 0xdd, 0xff},
+		Background:      color.RGBA{0x20, 0x22, 0x25, 0xff},
+		Border:          color.RGBA{0x5f, 0x63, 0x68, 0xff},
+		HoverForeground: color.RGBA{0x20, 0x22, 0x25, 0xff},
+		HoverBackground: color.RGBA{0x8a, 0xb4, 0xf8, 0xff},
+		Focus:           color.RGBA{0x8a, 0xb4, 0xf8, 0xff},
+		Disabled:        color.RGBA{0x6c, 0x6f, 0x73, 0xff},
+		Selection:       color.RGBA{0x26, 0x4f, 0x78, 0xff},
+		BorderWidth:     1,
+		TextPadding:     1,
+		ButtonPadding:   3,
+		LineSpacing:     3,
+	}
+}
+
+// ApplyFont sets gc's font and font size to t's, if t has them
+func (t *Theme) ApplyFont(gc draw2d.GraphicContext) {
+	if t.Font.Name != "" {
+		gc.SetFontData(t.Font)
+	}
+	if t.FontSize > 0 {
+		gc.SetFontSize(t.FontSize)
+	}
+}
+
+// LineHeight returns the height of a line of text drawn with gc's font size
+func (t *Theme) LineHeight(gc draw2d.GraphicContext) float64 {
+	return gc.GetFontSize() + t.LineSpacing
+}
+
+// Themed is implemented by widgets which can be restyled with a Theme. WidgetCollection passes its theme on
+// to every Themed widget registered with it.
+type Themed interface {
+	// SetTheme restyles the widget with t
+	SetTheme(t *Theme)
+}
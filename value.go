@@ -0,0 +1,67 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+// Observable holds a value and calls its listeners whenever it changes. Widgets embed it to implement
+// ValueWidget.
+type Observable[T comparable] struct {
+	value     T
+	listeners []func(T)
+}
+
+// NewObservable creates an Observable holding v
+func NewObservable[T comparable](v T) Observable[T] {
+	return Observable[T]{value: v}
+}
+
+// Get returns the value
+func (o *Observable[T]) Get() T {
+	return o.value
+}
+
+// Set sets the value, calling the listeners with it if it changed
+func (o *Observable[T]) Set(v T) {
+	if v == o.value {
+		return
+	}
+	o.value = v
+	for _, f := range o.listeners {
+		f(v)
+	}
+}
+
+// OnChange adds f to the functions called with the new value whenever it changes
+func (o *Observable[T]) OnChange(f func(T)) {
+	o.listeners = append(o.listeners, f)
+}
+
+// ValueWidget is implemented by widgets holding a value of type T, such as a Slider's float64 or a
+// Checkbox's bool
+type ValueWidget[T any] interface {
+	Widget
+	// Get returns the widget's value
+	Get() T
+	// Set sets the widget's value, it may be adjusted to one the widget supports
+	Set(v T)
+	// OnChange adds f to the functions called with the widget's new value whenever it changes, whether
+	// by Set or by the user
+	OnChange(f func(T))
+}
+
+// GetValue returns w's value if it's a ValueWidget of type T. ok is false if it isn't, which tells an
+// unsupported widget apart from one holding T's zero value.
+func GetValue[T any](w Widget) (v T, ok bool) {
+	vw, ok := w.(ValueWidget[T])
+	if !ok {
+		return v, false
+	}
+	return vw.Get(), true
+}
+
+// SetValue sets w's value if it's a ValueWidget of type T, returning false if it isn't
+func SetValue[T any](w Widget, v T) bool {
+	vw, ok := w.(ValueWidget[T])
+	if ok {
+		vw.Set(v)
+	}
+	return ok
+}
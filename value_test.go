@@ -0,0 +1,44 @@
+// Copyright (c) 2016, redstarcoder
+package draw2dui
+
+import "testing"
+
+// valueWidget is a dummyWidget holding a float64
+type valueWidget struct {
+	*dummyWidget
+	Observable[float64]
+}
+
+func TestObservable(t *testing.T) {
+	o := NewObservable("a")
+	var got []string
+	o.OnChange(func(v string) { got = append(got, v) })
+	o.OnChange(func(v string) { got = append(got, v+"!") })
+	o.Set("a")
+	o.Set("b")
+	if o.Get() != "b" {
+		t.Errorf("Get returned %q, should be %q.", o.Get(), "b")
+	}
+	checkOrder(t, got, "b", "b!")
+}
+
+func TestGetValue(t *testing.T) {
+	vw := &valueWidget{dummyWidget: newDummyWidget("a", 0, 0, 10, 10, nil)}
+	var changed float64
+	vw.OnChange(func(v float64) { changed = v })
+	if !SetValue(vw, 0.5) || changed != 0.5 {
+		t.Error("SetValue should set a ValueWidget[float64] and notify its listeners.")
+	}
+	if v, ok := GetValue[float64](vw); !ok || v != 0.5 {
+		t.Errorf("GetValue returned %v, %v, should be 0.5, true.", v, ok)
+	}
+	if SetValue(vw, "0.5") {
+		t.Error("SetValue should fail with the wrong type.")
+	}
+	if _, ok := GetValue[bool](vw); ok {
+		t.Error("GetValue should fail with the wrong type.")
+	}
+	if _, ok := GetValue[float64](vw.dummyWidget); ok {
+		t.Error("GetValue should fail on widgets without a value.")
+	}
+}
@@ -2,31 +2,66 @@
 package draw2dui
 
 import (
-	"github.com/go-gl/glfw/v3.1/glfw"
+	"math"
+	"reflect"
+	"sync"
+
 	"github.com/llgcode/draw2d"
+	"github.com/llgcode/draw2d/draw2dkit"
 )
 
-// TODO Add a method for removing widgets?
-
 // WidgetCollection is a struct for managing many widgets at once. It has many helper methods for handling
 // mouse and keyboard events.
 type WidgetCollection struct {
 	gc      *draw2d.GraphicContext
-	window  *glfw.Window
-	widgets map[string]Widget
+	window  CursorSetter
+	widgets []Widget // widgets is ordered from the bottom to the top of the z-order
+
+	mx, my        float64
+	hasCursor     bool
+	forceRedraw   bool
+	selected      string
+	hovered       string   // hovered is the widget which last took the cursor in MMove
+	dragTarget    string   // dragTarget is the widget which last accepted a DragOver event
+	tabOrder      []string // tabOrder is nil when it follows the z-order
+	tabWrap       bool     // tabWrap is whether Tab wraps around to the start of the tab order
+	handlers      map[string]*Handlers
+	damage        []Rect          // damage holds the areas of the screen which need redrawing
+	bounds        map[string]Rect // bounds holds each widget's bounds when it was last drawn
+	layout        Layout
+	theme         *Theme
+	clock         Clock
+	clipboard     Clipboard
+	width, height float64 // width and height are the collection's size from the last Reshape
 
-	mx, my      float64
-	hasCursor   bool
-	forceRedraw bool
-	selected    string
+	postLock sync.Mutex // postLock guards posted and waker, which Post uses from other goroutines
+	posted   []func()
+	waker    Waker
 }
 
-// NewWidgetCollection creates a new widget collection and registers all the widgets
-func NewWidgetCollection(gc *draw2d.GraphicContext, window *glfw.Window, widgets ...Widget) *WidgetCollection {
+// NewWidgetCollection creates a new widget collection and registers all the widgets. window is used to set
+// the mouse cursor, and may be nil, or a nil pointer, in which case the collection runs headless (see
+// NewImageContext). If window implements Waker, it's used by Post, and if it implements Clipboard, it's the
+// collection's Clipboard instead of a MemoryClipboard.
+func NewWidgetCollection(gc *draw2d.GraphicContext, window CursorSetter, widgets ...Widget) *WidgetCollection {
+	if v := reflect.ValueOf(window); v.Kind() == reflect.Ptr && v.IsNil() {
+		window = nil
+	}
 	wc := &WidgetCollection{
-		gc:      gc,
-		window:  window,
-		widgets: make(map[string]Widget, len(widgets)),
+		gc:       gc,
+		window:   window,
+		widgets:  make([]Widget, 0, len(widgets)),
+		handlers: make(map[string]*Handlers),
+		bounds:   make(map[string]Rect, len(widgets)),
+		theme:    DefaultTheme(),
+		clock:    RealClock(),
+		tabWrap:  true,
+	}
+	wc.waker, _ = window.(Waker)
+	if c, ok := window.(Clipboard); ok {
+		wc.clipboard = c
+	} else {
+		wc.clipboard = &MemoryClipboard{}
 	}
 	for _, w := range widgets {
 		wc.Register(w)
@@ -34,25 +69,313 @@ func NewWidgetCollection(gc *draw2d.GraphicContext, window *glfw.Window, widgets
 	return wc
 }
 
-// Register adds a widget to the collection
+// Register adds a widget to the top of the collection, styles it with the collection's Theme and gives it the
+// collection's Clock and Clipboard. If a widget with the same name is already registered, it's replaced in place.
 func (wc *WidgetCollection) Register(widget Widget) {
+	wc.adopt(widget)
 	if len(wc.selected) == 0 {
 		wc.selected = widget.Name()
 	}
-	wc.widgets[widget.Name()] = widget
+	if i := wc.Index(widget.Name()); i >= 0 {
+		wc.damageWidget(wc.widgets[i])
+		delete(wc.bounds, widget.Name())
+		wc.widgets[i] = widget
+		return
+	}
+	wc.widgets = append(wc.widgets, widget)
+}
+
+// adopt passes the collection's Theme, Clock and Clipboard on to widget, if it uses them
+func (wc *WidgetCollection) adopt(widget Widget) {
+	if t, ok := widget.(Themed); ok {
+		t.SetTheme(wc.theme)
+	}
+	if c, ok := widget.(Clocked); ok {
+		c.SetClock(wc.clock)
+	}
+	if cu, ok := widget.(ClipboardUser); ok {
+		cu.SetClipboard(wc.clipboard)
+	}
+}
+
+// Remove removes the widget with the name from the collection and clears its area of the screen, returning
+// the widget, or nil if there isn't one. If the widget was selected, nothing is selected afterwards, and if
+// it had the mouse cursor, the cursor is reset.
+func (wc *WidgetCollection) Remove(name string) Widget {
+	i := wc.Index(name)
+	if i < 0 {
+		return nil
+	}
+	w := wc.widgets[i]
+	wc.widgets = append(wc.widgets[:i], wc.widgets[i+1:]...)
+	wc.release(w, "")
+	delete(wc.handlers, name)
+	return w
+}
+
+// Replace replaces the widget with the name with widget, keeping its position in the z-order, and clears the
+// old widget's area of the screen. If the old widget was selected, widget becomes selected, and if it had
+// the mouse cursor, the cursor is reset. Returns false if there's no such widget.
+func (wc *WidgetCollection) Replace(name string, widget Widget) bool {
+	i := wc.Index(name)
+	if i < 0 {
+		return false
+	}
+	wc.adopt(widget)
+	w := wc.widgets[i]
+	wc.widgets[i] = widget
+	wc.release(w, widget.Name())
+	delete(wc.handlers, name)
+	return true
+}
+
+// release marks w's area of the screen for clearing and hands its selection to newSelected and its cursor
+// back to the collection. It must be called after w leaves the collection.
+func (wc *WidgetCollection) release(w Widget, newSelected string) {
+	wc.damageWidget(w)
+	delete(wc.bounds, w.Name())
+
+	if w.Name() == wc.selected {
+		wc.setSelected(w, newSelected)
+	}
+	if w.Name() == wc.dragTarget {
+		wc.dragTarget = ""
+	}
+	if w.Name() == wc.hovered {
+		wc.hovered = ""
+		if wc.window != nil {
+			wc.window.SetCursor(ArrowCursor)
+		}
+		wc.hasCursor = true
+	}
+}
+
+// SetHandlers sets the callbacks for the widget with the name, which are called in addition to the widget's
+// own Handlers if it has any. They're forgotten when the widget is removed or replaced.
+func (wc *WidgetCollection) SetHandlers(name string, h Handlers) {
+	wc.handlers[name] = &h
+}
+
+// GetHandlers returns the callbacks set for the widget with the name with SetHandlers
+func (wc *WidgetCollection) GetHandlers(name string) Handlers {
+	if h, ok := wc.handlers[name]; ok {
+		return *h
+	}
+	return Handlers{}
+}
+
+// fire calls w's callbacks for event, first the collection's and then w's own
+func (wc *WidgetCollection) fire(w Widget, event Event) {
+	if h, ok := wc.handlers[w.Name()]; ok {
+		h.Call(w, event)
+	}
+	if hw, ok := w.(HandlerWidget); ok {
+		hw.GetHandlers().Call(w, event)
+	}
+}
+
+// Get returns the widget with the name, or nil if there isn't one
+func (wc *WidgetCollection) Get(name string) Widget {
+	if i := wc.Index(name); i >= 0 {
+		return wc.widgets[i]
+	}
+	return nil
+}
+
+// Widgets returns all the widgets in the collection, ordered from the bottom to the top
+func (wc *WidgetCollection) Widgets() []Widget {
+	return append([]Widget(nil), wc.widgets...)
+}
+
+// Index returns the position of the widget with the name in the z-order, where 0 is the bottom. Returns -1
+// if there's no such widget.
+func (wc *WidgetCollection) Index(name string) int {
+	for i, w := range wc.widgets {
+		if w.Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// MoveTo moves the widget with the name to position i in the z-order, where 0 is the bottom. i is clamped
+// to the collection's bounds. Returns false if there's no such widget.
+func (wc *WidgetCollection) MoveTo(name string, i int) bool {
+	from := wc.Index(name)
+	if from < 0 {
+		return false
+	}
+	if i < 0 {
+		i = 0
+	} else if i >= len(wc.widgets) {
+		i = len(wc.widgets) - 1
+	}
+	if i == from {
+		return true
+	}
+	w := wc.widgets[from]
+	if i < from {
+		copy(wc.widgets[i+1:from+1], wc.widgets[i:from])
+	} else {
+		copy(wc.widgets[from:i], wc.widgets[from+1:i+1])
+	}
+	wc.widgets[i] = w
+	wc.damageWidget(w)
+	return true
+}
+
+// Raise moves the widget with the name to the top of the z-order. Returns false if there's no such widget.
+func (wc *WidgetCollection) Raise(name string) bool {
+	return wc.MoveTo(name, len(wc.widgets)-1)
+}
+
+// Lower moves the widget with the name to the bottom of the z-order. Returns false if there's no such
+// widget.
+func (wc *WidgetCollection) Lower(name string) bool {
+	return wc.MoveTo(name, 0)
+}
+
+// SetOrder reorders the collection so the named widgets come first, from the bottom up, in the order given.
+// Widgets which aren't named keep their relative order above them. Unknown names are ignored.
+func (wc *WidgetCollection) SetOrder(names ...string) {
+	i := 0
+	for _, name := range names {
+		if from := wc.Index(name); from >= i {
+			wc.MoveTo(name, i)
+			i++
+		}
+	}
+}
+
+// Invalidate marks an area of the screen as needing to be redrawn by the next call to Draw
+func (wc *WidgetCollection) Invalidate(x, y, width, height float64) {
+	wc.damage = append(wc.damage, Rect{x, y, x + width, y + height})
+}
+
+// damageWidget marks the areas w was last drawn to and will be drawn to as needing to be redrawn
+func (wc *WidgetCollection) damageWidget(w Widget) {
+	if b, ok := wc.bounds[w.Name()]; ok {
+		wc.damage = append(wc.damage, b)
+	}
+	wc.damage = append(wc.damage, widgetBounds(w))
+}
+
+// widgetDamage returns the areas the next Draw has to redraw because of w
+func (wc *WidgetCollection) widgetDamage(w Widget) []Rect {
+	b := widgetBounds(w)
+	prev, drawn := wc.bounds[w.Name()]
+	switch {
+	case wc.forceRedraw || !drawn:
+		return []Rect{b}
+	case prev != b:
+		return []Rect{prev, b}
+	}
+	if r, ok := w.(Redrawer); !ok || r.NeedsRedraw() {
+		if d, ok := w.(Damager); ok {
+			return d.Damage()
+		}
+		return []Rect{b}
+	}
+	return nil
 }
 
-// Draw draws all the widgets to the screen
+// Damage returns the areas of the screen the next Draw would redraw, before they're merged
+func (wc *WidgetCollection) Damage() []Rect {
+	damage := append([]Rect(nil), wc.damage...)
+	if wc.forceRedraw && wc.width > 0 && wc.height > 0 {
+		damage = append(damage, Rect{0, 0, wc.width, wc.height})
+	}
+	for _, w := range wc.widgets {
+		damage = append(damage, wc.widgetDamage(w)...)
+	}
+	return damage
+}
+
+// NeedsRedraw returns true if the next Draw would redraw anything
+func (wc *WidgetCollection) NeedsRedraw() bool {
+	if wc.forceRedraw || len(wc.damage) > 0 {
+		return true
+	}
+	for _, w := range wc.widgets {
+		if len(wc.widgetDamage(w)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Draw redraws the parts of the screen which changed since the last Draw: the areas of widgets which need a
+// redraw, moved or were removed, and anything passed to Invalidate. Everything is redrawn after Reshape,
+// Refresh and SetTheme. A damaged area is cleared, then every
+// widget overlapping it is redrawn from the bottom to the top. If the draw2d.GraphicContext implements
+// Clipper, drawing is clipped to the damaged area and to each widget's bounds, otherwise the damaged area
+// grows to cover any widgets overlapping it.
 func (wc *WidgetCollection) Draw() {
+	wc.damage = wc.Damage()
+
+	gc := *wc.gc
+	clipper, canClip := gc.(Clipper)
+	for _, r := range wc.mergeDamage(!canClip) {
+		gc.Save()
+		if canClip {
+			clipper.ClipRect(r.X0, r.Y0, r.X1, r.Y1)
+		}
+		gc.BeginPath()
+		gc.SetFillColor(wc.theme.Background)
+		draw2dkit.Rectangle(gc, r.X0, r.Y0, r.X1, r.Y1)
+		gc.Fill()
+		for _, w := range wc.widgets {
+			if b := widgetBounds(w); b.Overlaps(r) {
+				gc.Save()
+				if canClip {
+					clipper.ClipRect(b.X0, b.Y0, b.X1, b.Y1)
+				}
+				w.Draw(w.Name() == wc.selected, true)
+				gc.Restore()
+			}
+		}
+		gc.Restore()
+	}
+
 	for _, w := range wc.widgets {
-		w.Draw(w.Name() == wc.selected, wc.forceRedraw)
+		wc.bounds[w.Name()] = widgetBounds(w)
 	}
+	wc.damage = wc.damage[:0]
 	wc.forceRedraw = false
 }
 
-// Handle processes all the idle events for every widget in the collection. Returns whether it requests a
-// call to WidgetCollection.Draw or not.
+// mergeDamage merges overlapping damaged areas, returning the result. If grow is true, damaged areas also
+// grow to cover the bounds of any widgets overlapping them, since those widgets can't be clipped.
+func (wc *WidgetCollection) mergeDamage(grow bool) []Rect {
+	damage := wc.damage
+	for merged := true; merged; {
+		merged = false
+		for i := 0; i < len(damage); i++ {
+			if grow {
+				for _, w := range wc.widgets {
+					if b := widgetBounds(w); b.Overlaps(damage[i]) && b.Union(damage[i]) != damage[i] {
+						damage[i] = b.Union(damage[i])
+						merged = true
+					}
+				}
+			}
+			for j := i + 1; j < len(damage); j++ {
+				if damage[i].Overlaps(damage[j]) {
+					damage[i] = damage[i].Union(damage[j])
+					damage = append(damage[:j], damage[j+1:]...)
+					j--
+					merged = true
+				}
+			}
+		}
+	}
+	return damage
+}
+
+// Handle runs the functions queued by Post, then processes all the idle events for every widget in the
+// collection. Returns whether it requests a call to WidgetCollection.Draw or not.
 func (wc *WidgetCollection) Handle() (redraw bool) {
+	redraw = wc.runPosted()
 	for _, w := range wc.widgets {
 		if w.Handle(w.Name() == wc.selected) {
 			redraw = true
@@ -61,25 +384,177 @@ func (wc *WidgetCollection) Handle() (redraw bool) {
 	return
 }
 
-// KeyPress has the selected widget process a KeyPress event, returning the selected widget and the event if
-// it isn't EventNone.
-func (wc *WidgetCollection) KeyPress(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) (Widget, Event) {
-	if len(wc.selected) == 0 {
+// KeyPress has the selected widget process a KeyPress event if it's enabled, returning the selected widget and
+// the event if it isn't EventNone. If the widget doesn't handle Tab, Tab and Shift+Tab move the selection to the next and
+// previous focusable widgets, as do the EventNext and EventPrevious events. The newly selected widget is then
+// returned with EventSelected. If the tab order doesn't wrap around (see SetTabWrap) and runs out, nothing is
+// selected and EventNext or EventPrevious is returned with a nil widget. A widget returning EventSelected,
+// such as a TabContainer moving the selection between its children, stays selected without its OnSelected
+// callbacks being called again.
+func (wc *WidgetCollection) KeyPress(key Key, action Action, mods ModifierKey) (Widget, Event) {
+	w := wc.Get(wc.selected)
+	event := EventNone
+	if w != nil && IsEnabled(w) {
+		event = w.KeyPress(key, action, mods)
+	}
+	if event == EventNone && key == KeyTab && action != Release {
+		if mods&ModShift != 0 {
+			event = EventPrevious
+		} else {
+			event = EventNext
+		}
+	}
+	switch event {
+	case EventNone:
 		return nil, EventNone
+	case EventNext, EventPrevious:
+		reverse := event == EventPrevious
+		if next := wc.nextFocus(reverse); next != nil {
+			wc.tabTo(next, reverse)
+			return next, EventSelected
+		}
+		if wc.tabWrap {
+			return nil, EventNone
+		}
+		wc.Select("")
+		return nil, event
+	case EventSelected:
+		return w, EventSelected
 	}
-	if w := wc.widgets[wc.selected]; w.KeyPress(key, action, mods) == EventAction {
-		return w, EventAction
+	wc.fire(w, event)
+	return w, event
+}
+
+// tabTo selects w after Tab moved the selection to it. If w is a TabContainer it selects one of its own
+// widgets too, its last if reverse is true.
+func (wc *WidgetCollection) tabTo(w Widget, reverse bool) {
+	wc.Select(w.Name())
+	if tc, ok := w.(TabContainer); ok {
+		tc.TabInto(reverse)
 	}
-	return nil, EventNone
 }
 
-// CharPress has the selected widget process a character, returning the selected widget and the event if it
-// isn't EventNone.
+// TabFirst selects the first focusable widget in the tab order, or the last if reverse is true, as if Tab
+// had moved the selection into the collection. Returns the selected widget, or nil if no widget can take the
+// focus.
+func (wc *WidgetCollection) TabFirst(reverse bool) Widget {
+	wc.Select("")
+	next := wc.nextFocus(reverse)
+	if next != nil {
+		wc.tabTo(next, reverse)
+	}
+	return next
+}
+
+// SetTabWrap sets whether Tab wraps around from the end of the tab order to the start, which it does by
+// default. Collections nested in a TabContainer don't wrap, so Tab can leave the container.
+func (wc *WidgetCollection) SetTabWrap(wrap bool) {
+	wc.tabWrap = wrap
+}
+
+// SetTabOrder sets the order Tab moves the selection through the named widgets in. Widgets which aren't
+// named can't be reached with Tab. Calling it with no names restores the default, which is the z-order from
+// the bottom up.
+func (wc *WidgetCollection) SetTabOrder(names ...string) {
+	if len(names) == 0 {
+		wc.tabOrder = nil
+		return
+	}
+	wc.tabOrder = append([]string(nil), names...)
+}
+
+// nextFocus returns the focusable widget after the selected one in the tab order, or before it if reverse
+// is true, wrapping around if wc.tabWrap is true. Returns nil if no widget can take the focus.
+func (wc *WidgetCollection) nextFocus(reverse bool) Widget {
+	var order []Widget
+	if wc.tabOrder == nil {
+		order = wc.widgets
+	} else {
+		for _, name := range wc.tabOrder {
+			if w := wc.Get(name); w != nil {
+				order = append(order, w)
+			}
+		}
+	}
+	if len(order) == 0 {
+		return nil
+	}
+
+	current := -1
+	for i, w := range order {
+		if w.Name() == wc.selected {
+			current = i
+			break
+		}
+	}
+	step := 1
+	if reverse {
+		step = -1
+		if current < 0 {
+			current = len(order)
+		}
+	}
+	for n := 1; n <= len(order); n++ {
+		i := current + step*n
+		if !wc.tabWrap && (i < 0 || i >= len(order)) {
+			return nil
+		}
+		i = (i%len(order) + len(order)) % len(order)
+		if w := order[i]; isFocusable(w) {
+			return w
+		}
+	}
+	return nil
+}
+
+// isFocusable checks if w is enabled and accepts keyboard focus
+func isFocusable(w Widget) bool {
+	if f, ok := w.(Focusable); ok && !f.Focusable() {
+		return false
+	}
+	return IsEnabled(w)
+}
+
+// Select selects the widget with the name, redrawing both widgets if the selection changed. An empty name
+// deselects everything. Returns false if there's no such widget.
+func (wc *WidgetCollection) Select(name string) bool {
+	if len(name) > 0 && wc.Index(name) < 0 {
+		return false
+	}
+	if name != wc.selected {
+		wc.setSelected(wc.Get(wc.selected), name)
+	}
+	return true
+}
+
+// setSelected moves the selection from old, which may be nil, to the widget with the name. It marks both
+// for redrawing and calls the OnFocusLost and OnSelected callbacks.
+func (wc *WidgetCollection) setSelected(old Widget, name string) {
+	wc.selected = name
+	if old != nil {
+		wc.damageWidget(old)
+		wc.fire(old, EventFocusLost)
+	}
+	if w := wc.Get(name); w != nil {
+		wc.damageWidget(w)
+		wc.fire(w, EventSelected)
+	}
+}
+
+// Selected returns the selected widget, or nil if nothing is selected
+func (wc *WidgetCollection) Selected() Widget {
+	return wc.Get(wc.selected)
+}
+
+// CharPress has the selected widget process a character if it's enabled, returning the selected widget and
+// the event if it isn't EventNone.
 func (wc *WidgetCollection) CharPress(char rune) (Widget, Event) {
-	if len(wc.selected) == 0 {
+	w := wc.Get(wc.selected)
+	if w == nil || !IsEnabled(w) {
 		return nil, EventNone
 	}
-	if w := wc.widgets[wc.selected]; w.CharPress(char) == EventAction {
+	if w.CharPress(char) == EventAction {
+		wc.fire(w, EventAction)
 		return w, EventAction
 	}
 	return nil, EventNone
@@ -87,62 +562,151 @@ func (wc *WidgetCollection) CharPress(char rune) (Widget, Event) {
 
 // MMove has all the widgets in the collection process a MouseMove event, returning the a widget and event
 // if the cursor changes. Always returns the moused-over widget, unless there isn't one, then it returns a
-// widget that returned EventAction, if any.
+// widget that returned EventAction, if any. Only the topmost widget under the mouse is moused-over; widgets
+// underneath it are sent a position outside of the screen.
 func (wc *WidgetCollection) MMove(xpos, ypos float64) (widget Widget, event Event) {
 	wc.mx, wc.my = xpos, ypos
 	hasCursor := true
-	for _, w := range wc.widgets {
-		if ev := w.MMove(xpos, ypos); ev == EventHasCursor {
+	for i := len(wc.widgets) - 1; i >= 0; i-- {
+		w := wc.widgets[i]
+		x, y := xpos, ypos
+		if !hasCursor {
+			x, y = math.Inf(-1), math.Inf(-1)
+		}
+		if ev := w.MMove(x, y); ev == EventHasCursor {
 			if wc.hasCursor {
 				wc.hasCursor = false
 				event = EventHasCursor
 			}
 			hasCursor = false
 			widget = w
+			wc.hovered = w.Name()
 		} else if widget == nil && ev == EventAction {
 			widget = w
 			event = EventAction
 		}
 	}
-	if hasCursor && !wc.hasCursor {
-		wc.window.SetCursor(glfw.CreateStandardCursor(int(glfw.ArrowCursor)))
-		wc.hasCursor = true
+	if hasCursor {
+		wc.hovered = ""
+		if !wc.hasCursor {
+			if wc.window != nil {
+				wc.window.SetCursor(ArrowCursor)
+			}
+			wc.hasCursor = true
+		}
 	}
 	return
 }
 
-// MClick has the all widgets in the collection process a MouseClick event, returning the a widget and event
-// if it isn't EventNone.
-func (wc *WidgetCollection) MClick(button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) (Widget, Event) {
-	for _, w := range wc.widgets {
-		switch event := w.MClick(wc.mx, wc.my, button, action, mods); event {
-		default:
-			return w, event
-		case EventSelected:
-			if w.Name() != wc.selected {
-				wc.selected = w.Name()
-				wc.forceRedraw = true
-			}
-			return w, EventSelected
-		case EventNone:
+// MClick has the widgets in the collection process a MouseClick event, returning the topmost widget under
+// the mouse and its event if it isn't EventNone. Only that widget is sent the mouse's position, the others
+// are sent a position outside of the screen so they see a click elsewhere, such as to stop dragging or
+// close. Disabled widgets aren't sent clicks, but still cover the widgets under them, so clicking one returns
+// EventNone. Pressing where no widget takes the click deselects everything, returning EventSelected with a
+// nil widget.
+func (wc *WidgetCollection) MClick(button MouseButton, action Action, mods ModifierKey) (Widget, Event) {
+	target := -1
+	for i := len(wc.widgets) - 1; i >= 0; i-- {
+		if wc.widgets[i].IsInside(wc.mx, wc.my) {
+			target = i
+			break
+		}
+	}
+	covered := target >= 0 && !IsEnabled(wc.widgets[target])
+	event := EventNone
+	for i := len(wc.widgets) - 1; i >= 0; i-- {
+		w := wc.widgets[i]
+		if !IsEnabled(w) {
+			continue
+		}
+		x, y := wc.mx, wc.my
+		if i != target {
+			x, y = math.Inf(-1), math.Inf(-1)
+		}
+		if ev := w.MClick(x, y, button, action, mods); i == target {
+			event = ev
 		}
 	}
-	if action == glfw.Press {
-		if len(wc.selected) > 0 {
-			wc.selected = ""
-			wc.forceRedraw = true
+	switch {
+	case covered:
+		return nil, EventNone
+	case event == EventNone:
+		if action == Press {
+			wc.Select("")
+			return nil, EventSelected
 		}
-		return nil, EventSelected
+		return nil, EventNone
+	case event == EventSelected:
+		w := wc.widgets[target]
+		wc.Select(w.Name())
+		return w, EventSelected
+	}
+	w := wc.widgets[target]
+	wc.fire(w, event)
+	return w, event
+}
+
+// Scroll has the topmost widget under the mouse process a Scroll event if it's an enabled Scroller, returning
+// it and the event if it isn't EventNone. See Scroller for xoff and yoff.
+func (wc *WidgetCollection) Scroll(xoff, yoff float64) (Widget, Event) {
+	for i := len(wc.widgets) - 1; i >= 0; i-- {
+		w := wc.widgets[i]
+		if !w.IsInside(wc.mx, wc.my) {
+			continue
+		}
+		if s, ok := w.(Scroller); ok && IsEnabled(w) {
+			if event := s.Scroll(xoff, yoff); event != EventNone {
+				wc.fire(w, event)
+				return w, event
+			}
+		}
+		break
 	}
 	return nil, EventNone
 }
 
-// Reshape should be called whenever the draw2d.GraphicContext is resized
-// TODO handle w & h
+// Reshape should be called whenever the draw2d.GraphicContext is resized or recreated. The collection's
+// Theme's font is applied to it again, and if the collection has a Layout, it's rearranged to fill the new
+// width and height.
 func (wc *WidgetCollection) Reshape(w, h int) {
+	wc.theme.ApplyFont(*wc.gc)
+	wc.width, wc.height = float64(w), float64(h)
+	if wc.layout != nil {
+		wc.layout.Arrange(0, 0, wc.width, wc.height)
+	}
 	wc.forceRedraw = true
 }
 
+// SetTheme restyles the collection and all of its widgets with t, rearranging them for their new sizes and
+// redrawing everything. Changes made to t afterwards take effect by calling SetTheme again.
+func (wc *WidgetCollection) SetTheme(t *Theme) {
+	wc.theme = t
+	t.ApplyFont(*wc.gc)
+	for _, w := range wc.widgets {
+		if themed, ok := w.(Themed); ok {
+			themed.SetTheme(t)
+		}
+	}
+	if wc.layout != nil && wc.width > 0 && wc.height > 0 {
+		wc.layout.Arrange(0, 0, wc.width, wc.height)
+	}
+	wc.forceRedraw = true
+}
+
+// Theme returns the collection's Theme
+func (wc *WidgetCollection) Theme() *Theme {
+	return wc.theme
+}
+
+// SetLayout sets the Layout which arranges the collection's widgets whenever it's reshaped, and arranges
+// them if the collection's size is already known. A nil Layout leaves widgets where they are.
+func (wc *WidgetCollection) SetLayout(l Layout) {
+	wc.layout = l
+	if l != nil && wc.width > 0 && wc.height > 0 {
+		l.Arrange(0, 0, wc.width, wc.height)
+	}
+}
+
 // Refresh should be called when a window refresh occurs (See: glfw.RefreshCallback)
 func (wc *WidgetCollection) Refresh() {
 	wc.forceRedraw = true
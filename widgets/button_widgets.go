@@ -2,50 +2,55 @@
 package widgets
 
 import (
-	"image/color"
-
-	"github.com/go-gl/gl/v2.1/gl"
-	"github.com/go-gl/glfw/v3.1/glfw"
 	"github.com/llgcode/draw2d"
 	"github.com/llgcode/draw2d/draw2dkit"
-	"github.com/redstarcoder/draw2dglkit"
 	"github.com/redstarcoder/draw2dui"
 )
 
 type Button struct {
+	draw2dui.Handlers
 	x, y, width, height        float64
 	enabled, redraw, hasCursor bool
 	shape                      *draw2d.Path
-	window, offscreen          *glfw.Window
+	window                     draw2dui.CursorSetter
 	gc                         *draw2d.GraphicContext // gc may be overwritten so it's a pointer to an interface
+	theme                      *draw2dui.Theme
 	name, text                 string
 }
 
-func NewButton(gc *draw2d.GraphicContext, window, offscreen *glfw.Window, x, y float64, text string) *Button {
+// NewButton creates a new Button widget. window may be nil to run headless.
+func NewButton(gc *draw2d.GraphicContext, window draw2dui.CursorSetter, x, y float64, text string) *Button {
 	Button := &Button{
-		gc:        gc,
-		window:    window,
-		offscreen: offscreen,
-		x:         x,
-		y:         y,
-		height:    (*gc).GetFontSize() + 13,
-		enabled:   true,
-		shape:     &draw2d.Path{},
-		redraw:    true,
-		name:      draw2dui.NameWidget("Button"),
-		text:      text,
+		gc:      gc,
+		window:  window,
+		x:       x,
+		y:       y,
+		enabled: true,
+		shape:   &draw2d.Path{},
+		redraw:  true,
+		name:    draw2dui.NameWidget("Button"),
+		text:    text,
 	}
-	Button.reshape()
+	Button.SetTheme(defaultTheme)
 	return Button
 }
 
+// NaturalSize returns the width and height btn needs to fit its text
+func (btn *Button) NaturalSize() (width, height float64) {
+	_, _, width, _ = (*btn.gc).GetStringBounds(btn.text)
+	return width + btn.theme.ButtonPadding*2,
+		btn.theme.LineHeight(*btn.gc) + (btn.theme.ButtonPadding+btn.theme.BorderWidth)*2
+}
+
+// resize fits btn to its text, then reshapes it
+func (btn *Button) resize() {
+	btn.width, btn.height = btn.NaturalSize()
+	btn.reshape()
+}
+
 // reshape recreates btn's path, which is used for drawing it to the screen
 func (btn *Button) reshape() {
 	btn.shape = &draw2d.Path{}
-	// Recalulate width
-	_, _, btn.width, _ = (*btn.gc).GetStringBounds(btn.text)
-	btn.width += 6
-
 	draw2dkit.Rectangle(btn.shape, btn.x, btn.y, btn.x+btn.width-1, btn.y+btn.height-1)
 	btn.redraw = true
 }
@@ -55,48 +60,38 @@ func (btn *Button) Name() string {
 	return btn.name
 }
 
+// NeedsRedraw returns true if btn changed since it was last drawn
+func (btn *Button) NeedsRedraw() bool {
+	return btn.redraw
+}
+
 // Draw draws the widget, selected determines if the widget displays as selected or not, and forceRedraw
 // forces a full redraw of the widget.
-// TODO draw dotted border if selected
 func (btn *Button) Draw(selected, forceRedraw bool) {
 	if btn.redraw || forceRedraw {
 		gc := *btn.gc
 		gc.Save()
-		btn.clear(gc, false)
-		gl.LineWidth(1)
-		var fg, bg color.RGBA
-		if btn.hasCursor {
-			fg = color.RGBA{255, 255, 255, 0xff}
-			bg = color.RGBA{0, 0, 0, 0xff}
-		} else {
-			fg = color.RGBA{0, 0, 0, 0xff}
-			bg = color.RGBA{255, 255, 255, 0xff}
+		gc.SetLineWidth(btn.theme.BorderWidth)
+		fg, bg := textColor(btn.theme, btn.enabled), btn.theme.Background
+		if btn.hasCursor && btn.enabled {
+			fg, bg = btn.theme.HoverForeground, btn.theme.HoverBackground
 		}
 		gc.SetFillColor(bg)
-		gc.SetStrokeColor(fg)
+		gc.SetStrokeColor(borderColor(btn.theme, selected, btn.enabled))
 		gc.FillStroke(btn.shape)
 		gc.SetFillColor(fg)
-		gc.FillStringAt(btn.text, btn.x+3, btn.y+6+gc.GetFontSize())
+		_, _, textWidth, _ := gc.GetStringBounds(btn.text)
+		gc.FillStringAt(btn.text, btn.x+(btn.width-textWidth)/2, btn.y+btn.theme.ButtonPadding+btn.theme.LineHeight(gc))
 		gc.Restore()
 
 		btn.redraw = false
 	}
 }
 
-// clear clears btn's border to make redrawing look more consistent without a complete screen clear. If fill
-// is true, it also fills the shape with white.
-func (btn *Button) clear(gc draw2d.GraphicContext, fill bool) {
-	gl.LineWidth(3)
-	if !fill {
-		gc.SetStrokeColor(color.RGBA{255, 255, 255, 0xff})
-		gc.Stroke(btn.shape)
-	} else {
-		gc.Save()
-		gc.SetStrokeColor(color.RGBA{255, 255, 255, 0xff})
-		gc.SetFillColor(color.RGBA{255, 255, 255, 0xff})
-		gc.FillStroke(btn.shape)
-		gc.Restore()
-	}
+// SetTheme restyles btn with t
+func (btn *Button) SetTheme(t *draw2dui.Theme) {
+	btn.theme = t
+	btn.resize()
 }
 
 // Handle returns false
@@ -105,11 +100,11 @@ func (btn *Button) Handle(selected bool) bool {
 }
 
 // KeyPress has the widget process a KeyPress event
-func (btn *Button) KeyPress(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) draw2dui.Event {
-	if action == glfw.Release {
+func (btn *Button) KeyPress(key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if action == draw2dui.Release {
 		return draw2dui.EventNone
 	}
-	if key == glfw.KeyEnter {
+	if key == draw2dui.KeyEnter {
 		// TODO make the button look like it got pressed
 		return draw2dui.EventConfirm
 	}
@@ -132,7 +127,7 @@ func (btn *Button) MMove(xpos, ypos float64) draw2dui.Event {
 		return draw2dui.EventNone
 	}
 	if !btn.hasCursor {
-		btn.window.SetCursor(glfw.CreateStandardCursor(int(glfw.HandCursor)))
+		setCursor(btn.window, draw2dui.HandCursor)
 		btn.hasCursor = true
 		btn.redraw = true
 		return draw2dui.EventHasCursor
@@ -143,8 +138,8 @@ func (btn *Button) MMove(xpos, ypos float64) draw2dui.Event {
 }
 
 // MClick has the widget process a MouseClick event
-func (btn *Button) MClick(xpos, ypos float64, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) draw2dui.Event {
-	if button == glfw.MouseButtonLeft && action == glfw.Press {
+func (btn *Button) MClick(xpos, ypos float64, button draw2dui.MouseButton, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if button == draw2dui.MouseButtonLeft && action == draw2dui.Press {
 		btn.redraw = true
 		if !btn.IsInside(xpos, ypos) {
 			return draw2dui.EventNone
@@ -158,7 +153,6 @@ func (btn *Button) MClick(xpos, ypos float64, button glfw.MouseButton, action gl
 
 // SetPos changes the widget's x, y coordinates
 func (btn *Button) SetPos(x, y float64) {
-	btn.clear(*btn.gc, true)
 	btn.x, btn.y = x, y
 	btn.reshape()
 }
@@ -168,11 +162,11 @@ func (btn *Button) GetPos() (float64, float64) {
 	return btn.x, btn.y
 }
 
-// SetDimensions sets btn's drawn width and height
+// SetDimensions sets btn's drawn width and height. Its text is centered in any extra width. Restyling btn
+// fits its width to its text again.
 func (btn *Button) SetDimensions(w, h float64) {
-	btn.clear(*btn.gc, true)
 	btn.width, btn.height = w, h
-	btn.reshape() // reshape overwrites width
+	btn.reshape()
 }
 
 // GetDimensions returns btn's drawn width and height
@@ -180,9 +174,9 @@ func (btn *Button) GetDimensions() (float64, float64) {
 	return btn.width, btn.height
 }
 
-// IsInside checks if point x, y is inside of the widget's boundaries. It uses btn.offscreen as a pallet
+// IsInside checks if point x, y is inside of the widget's boundaries
 func (btn *Button) IsInside(x, y float64) bool {
-	return draw2dglkit.IsPointInShape(*btn.gc, btn.offscreen, x, y, btn.shape)
+	return draw2dui.IsPointInPath(btn.shape, x, y)
 }
 
 // SetString sets btn's text, using btn.maxlen as the max length
@@ -196,24 +190,6 @@ func (btn *Button) GetString() string {
 	return btn.text
 }
 
-// SetInt does nothing
-func (btn *Button) SetInt(i int) {
-}
-
-// GetInt returns -1
-func (btn *Button) GetInt() int {
-	return -1
-}
-
-// SetData does nothing
-func (btn *Button) SetData(d interface{}) {
-}
-
-// GetData returns nil
-func (btn *Button) GetData() interface{} {
-	return nil
-}
-
 // SetEnabled enables or disables the widget
 func (btn *Button) SetEnabled(enabled bool) {
 	if btn.enabled != enabled {
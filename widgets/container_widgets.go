@@ -0,0 +1,288 @@
+// Copyright (c) 2016, redstarcoder
+package widgets
+
+import (
+	"math"
+	"time"
+
+	"github.com/llgcode/draw2d"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/redstarcoder/draw2dui"
+)
+
+// Panel is a widget which holds other widgets, including other Panels. Its children are positioned relative
+// to the Panel's top left corner, are sent events in those local coordinates, and are only drawn inside of
+// the Panel if the draw2d.GraphicContext implements draw2dui.Clipper. Events returned by children are
+// returned by the Panel too, so they reach the Panel's handlers after the child's.
+type Panel struct {
+	draw2dui.Handlers
+	x, y, width, height        float64
+	enabled, redraw, hasCursor bool
+	shape                      *draw2d.Path
+	window                     draw2dui.CursorSetter
+	gc                         *draw2d.GraphicContext // gc may be overwritten so it's a pointer to an interface
+	theme                      *draw2dui.Theme
+	name                       string
+	children                   *draw2dui.WidgetCollection
+}
+
+// NewPanel creates a new Panel widget holding children. window may be nil to run headless.
+func NewPanel(gc *draw2d.GraphicContext, window draw2dui.CursorSetter, x, y, width, height float64, children ...draw2dui.Widget) *Panel {
+	p := &Panel{
+		gc:      gc,
+		window:  window,
+		x:       x,
+		y:       y,
+		width:   width,
+		height:  height,
+		enabled: true,
+		redraw:  true,
+		theme:   defaultTheme,
+		name:    draw2dui.NameWidget("Panel"),
+	}
+	p.children = draw2dui.NewWidgetCollection(gc, panelCursor{p}, children...)
+	p.children.SetTabWrap(false)
+	p.children.Reshape(int(width), int(height))
+	p.reshape()
+	return p
+}
+
+// reshape recreates p's path, which is used for drawing it to the screen
+func (p *Panel) reshape() {
+	p.shape = &draw2d.Path{}
+	draw2dkit.Rectangle(p.shape, p.x, p.y, p.x+p.width-1, p.y+p.height-1)
+	p.redraw = true
+}
+
+// Children returns the WidgetCollection holding p's children. Widgets can be added, removed and laid out
+// with it, using coordinates relative to p's top left corner.
+func (p *Panel) Children() *draw2dui.WidgetCollection {
+	return p.children
+}
+
+// Name returns p's name
+func (p *Panel) Name() string {
+	return p.name
+}
+
+// NeedsRedraw returns true if p or any of its children changed since they were last drawn
+func (p *Panel) NeedsRedraw() bool {
+	return p.redraw || p.children.NeedsRedraw()
+}
+
+// Damage returns the areas of the screen p needs redrawn: all of it if it changed itself, otherwise only the
+// areas of its children which changed
+func (p *Panel) Damage() []draw2dui.Rect {
+	margin := p.theme.BorderWidth
+	bounds := draw2dui.Rect{X0: p.x - margin, Y0: p.y - margin, X1: p.x + p.width + margin, Y1: p.y + p.height + margin}
+	if p.redraw {
+		return []draw2dui.Rect{bounds}
+	}
+	var damage []draw2dui.Rect
+	for _, r := range p.children.Damage() {
+		r = draw2dui.Rect{X0: r.X0 + p.x, Y0: r.Y0 + p.y, X1: r.X1 + p.x, Y1: r.Y1 + p.y}.Intersect(bounds)
+		if !r.Empty() {
+			damage = append(damage, r)
+		}
+	}
+	if len(damage) == 0 {
+		return []draw2dui.Rect{bounds} // children outside of p are still redrawn with it, so they're cleaned
+	}
+	return damage
+}
+
+// Draw draws the widget and its children, selected determines if the widget displays as selected or not,
+// and forceRedraw forces a full redraw of the widget. The border is drawn last, so children drawn next to it
+// don't cover it.
+func (p *Panel) Draw(selected, forceRedraw bool) {
+	if !p.redraw && !forceRedraw && !p.children.NeedsRedraw() {
+		return
+	}
+	gc := *p.gc
+	gc.Save()
+	if clipper, ok := gc.(draw2dui.Clipper); ok {
+		clipper.ClipRect(p.x, p.y, p.x+p.width, p.y+p.height)
+	}
+	if p.redraw || forceRedraw {
+		gc.SetFillColor(p.theme.Background)
+		gc.Fill(p.shape)
+		p.children.Refresh()
+		p.redraw = false
+	}
+	gc.Save()
+	gc.Translate(p.x, p.y)
+	p.children.Draw()
+	gc.Restore()
+	gc.SetLineWidth(p.theme.BorderWidth)
+	gc.SetStrokeColor(borderColor(p.theme, false, p.enabled))
+	gc.Stroke(p.shape)
+	gc.Restore()
+}
+
+// SetTheme restyles p and its children with t
+func (p *Panel) SetTheme(t *draw2dui.Theme) {
+	p.theme = t
+	p.children.SetTheme(t)
+	p.redraw = true
+}
+
+// Handle processes the idle actions of p's children. Its selected child is deselected once p isn't selected.
+func (p *Panel) Handle(selected bool) bool {
+	if !selected && p.children.Selected() != nil {
+		p.children.Select("")
+	}
+	return p.children.Handle() || p.redraw
+}
+
+// SetClock sets the Clock p's children tell the time with
+func (p *Panel) SetClock(c draw2dui.Clock) {
+	p.children.SetClock(c)
+}
+
+// SetClipboard sets the Clipboard p's children copy to and paste from
+func (p *Panel) SetClipboard(c draw2dui.Clipboard) {
+	p.children.SetClipboard(c)
+}
+
+// NextDeadline returns the earliest time one of p's children needs p's Handle method called
+func (p *Panel) NextDeadline(selected bool) (time.Time, bool) {
+	return p.children.NextDeadline()
+}
+
+// TabInto selects p's first focusable child, or its last if reverse is true
+func (p *Panel) TabInto(reverse bool) {
+	p.children.TabFirst(reverse)
+}
+
+// KeyPress has p's selected child process a KeyPress event. Tab moves the selection between p's children,
+// returning draw2dui.EventNext or draw2dui.EventPrevious once it moves past the last or first of them, so
+// the selection leaves p.
+func (p *Panel) KeyPress(key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if !p.enabled {
+		return draw2dui.EventNone
+	}
+	_, event := p.children.KeyPress(key, action, mods)
+	return event
+}
+
+// CharPress has p's selected child process a character
+func (p *Panel) CharPress(char rune) draw2dui.Event {
+	if !p.enabled {
+		return draw2dui.EventNone
+	}
+	_, event := p.children.CharPress(char)
+	return event
+}
+
+// MMove has p's children process a MouseMove event. p takes the cursor whenever it's inside of p, leaving
+// it to a child if one is under it.
+func (p *Panel) MMove(xpos, ypos float64) draw2dui.Event {
+	inside := p.enabled && p.IsInside(xpos, ypos)
+	if !inside {
+		xpos, ypos = math.Inf(-1), math.Inf(-1)
+	}
+	hadCursor := p.hasCursor
+	p.hasCursor = inside
+	p.children.MMove(xpos-p.x, ypos-p.y)
+	if hadCursor && !inside {
+		return draw2dui.EventAction
+	} else if !inside {
+		return draw2dui.EventNone
+	}
+	return draw2dui.EventHasCursor
+}
+
+// panelCursor is the draw2dui.CursorSetter of a Panel's WidgetCollection. It only changes the cursor while
+// the mouse is inside of the Panel, so the collection doesn't override widgets outside of it.
+type panelCursor struct {
+	p *Panel
+}
+
+// SetCursor changes the mouse cursor to shape if the mouse is inside of the Panel
+func (pc panelCursor) SetCursor(shape draw2dui.CursorShape) {
+	if pc.p.hasCursor {
+		setCursor(pc.p.window, shape)
+	}
+}
+
+// MClick has p's children process a MouseClick event. Pressing anywhere inside of p selects it. Clicks
+// outside of p are passed on too, so its children see them as clicks elsewhere.
+func (p *Panel) MClick(xpos, ypos float64, button draw2dui.MouseButton, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if !p.enabled {
+		return draw2dui.EventNone
+	}
+	_, event := p.children.MClick(button, action, mods)
+	if !p.IsInside(xpos, ypos) {
+		return draw2dui.EventNone
+	}
+	return event
+}
+
+// Scroll has the child under the mouse process a Scroll event
+func (p *Panel) Scroll(xoff, yoff float64) draw2dui.Event {
+	if !p.enabled {
+		return draw2dui.EventNone
+	}
+	_, event := p.children.Scroll(xoff, yoff)
+	return event
+}
+
+// DragOver has the child under the dragged files process a DragOver event, returning whether it accepts them
+func (p *Panel) DragOver(xpos, ypos float64, paths []string) bool {
+	return p.enabled && p.children.DragOver(xpos-p.x, ypos-p.y, paths) != nil
+}
+
+// DragLeave has the child last dragged over process a DragLeave event
+func (p *Panel) DragLeave() {
+	p.children.DragLeave()
+}
+
+// Drop has the child under the dropped files process a Drop event
+func (p *Panel) Drop(xpos, ypos float64, paths []string) draw2dui.Event {
+	if !p.enabled {
+		return draw2dui.EventNone
+	}
+	_, event := p.children.Drop(xpos-p.x, ypos-p.y, paths)
+	return event
+}
+
+// SetPos changes the widget's x, y coordinates. Its children move along with it.
+func (p *Panel) SetPos(x, y float64) {
+	p.x, p.y = x, y
+	p.reshape()
+}
+
+// GetPos retrieves the widget's x, y coordinates
+func (p *Panel) GetPos() (float64, float64) {
+	return p.x, p.y
+}
+
+// SetDimensions sets p's drawn width and height, rearranging its children if they have a draw2dui.Layout
+func (p *Panel) SetDimensions(w, h float64) {
+	p.width, p.height = w, h
+	p.children.Reshape(int(w), int(h))
+	p.reshape()
+}
+
+// GetDimensions returns p's drawn width and height
+func (p *Panel) GetDimensions() (float64, float64) {
+	return p.width, p.height
+}
+
+// IsInside checks if point x, y is inside of the widget's boundaries
+func (p *Panel) IsInside(x, y float64) bool {
+	return draw2dui.IsPointInPath(p.shape, x, y)
+}
+
+// SetEnabled enables or disables the widget. A disabled Panel doesn't pass any events on to its children.
+func (p *Panel) SetEnabled(enabled bool) {
+	if p.enabled != enabled {
+		p.enabled = enabled
+		p.redraw = true
+	}
+}
+
+// GetEnabled returns whether the widget is enabled or not
+func (p *Panel) GetEnabled() bool {
+	return p.enabled
+}
@@ -4,20 +4,24 @@ package widgets
 import (
 	"errors"
 	"log"
+	"math"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
-	"github.com/go-gl/gl/v2.1/gl"
 	"github.com/golang/freetype/truetype"
 	"github.com/llgcode/draw2d"
 	"github.com/llgcode/draw2d/draw2dbase"
-	"github.com/llgcode/draw2d/draw2dgl"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/redstarcoder/draw2dui"
 	"golang.org/x/image/math/fixed"
 )
 
-// FIXME Do away with this function
-// BUG(x) loadCurrentFont should take a GraphicContext interface
-func loadCurrentFont(gc *draw2dgl.GraphicContext) (*truetype.Font, error) {
+// loadCurrentFont returns gc's current font, falling back to the default font the same way draw2d's
+// backends do. It works with any draw2d.GraphicContext implementation.
+func loadCurrentFont(gc draw2d.GraphicContext) (*truetype.Font, error) {
 	font := draw2d.GetFont(gc.GetFontData())
 	if font == nil {
 		font = draw2d.GetFont(draw2dbase.DefaultFontData)
@@ -25,19 +29,22 @@ func loadCurrentFont(gc *draw2dgl.GraphicContext) (*truetype.Font, error) {
 	if font == nil {
 		return nil, errors.New("No font set, and no default font available.")
 	}
-	gc.SetFont(font)
-	gc.SetFontSize(gc.GetFontSize())
 	return font, nil
 }
 
+// fontScale returns the scale of gc's current font, as used for kerning. This is the value draw2d's
+// backends keep internally, calculated from the font size and DPI.
+func fontScale(gc draw2d.GraphicContext) fixed.Int26_6 {
+	return fixed.Int26_6(gc.GetFontSize() * float64(gc.GetDPI()) * (64.0 / 72.0))
+}
+
 func fUnitsToFloat64(x fixed.Int26_6) float64 {
 	scaled := x << 2
 	return float64(scaled/256) + float64(scaled%256)/256.0
 }
 
 // fillStringAtWidth draws the text at the specified point (x, y), stopping before width is exceeded
-func fillStringAtWidth(_gc draw2d.GraphicContext, text string, x, y, width float64) float64 {
-	gc := _gc.(*draw2dgl.GraphicContext)
+func fillStringAtWidth(gc draw2d.GraphicContext, text string, x, y, width float64) float64 {
 	f, err := loadCurrentFont(gc)
 	if err != nil {
 		log.Println(err)
@@ -49,7 +56,7 @@ func fillStringAtWidth(_gc draw2d.GraphicContext, text string, x, y, width float
 	for _, r := range text {
 		index := f.Index(r)
 		if hasPrev {
-			x += fUnitsToFloat64(f.Kern(fixed.Int26_6(gc.Current.Scale), prev, index))
+			x += fUnitsToFloat64(f.Kern(fontScale(gc), prev, index))
 		}
 		glyph := draw2dbase.FetchGlyph(gc, fontName, r)
 		if x+glyph.Width-startx > width {
@@ -62,8 +69,7 @@ func fillStringAtWidth(_gc draw2d.GraphicContext, text string, x, y, width float
 }
 
 // fillStringAtWidthCursor draws the text at the specified point (x, y), stopping before width is exceeded.
-func fillStringAtWidthCursor(_gc draw2d.GraphicContext, c *Cursor, x, y, width float64) {
-	gc := _gc.(*draw2dgl.GraphicContext)
+func fillStringAtWidthCursor(gc draw2d.GraphicContext, c *Cursor, x, y, width float64) {
 	var last_i int = 1
 	f, err := loadCurrentFont(gc)
 	if err != nil {
@@ -79,7 +85,7 @@ func fillStringAtWidthCursor(_gc draw2d.GraphicContext, c *Cursor, x, y, width f
 	for i, r := range text {
 		index := f.Index(r)
 		if hasPrev {
-			x += fUnitsToFloat64(f.Kern(fixed.Int26_6(gc.Current.Scale), prev, index))
+			x += fUnitsToFloat64(f.Kern(fontScale(gc), prev, index))
 		}
 		if i == cindex {
 			cx = x + 1
@@ -96,11 +102,7 @@ func fillStringAtWidthCursor(_gc draw2d.GraphicContext, c *Cursor, x, y, width f
 		if cindex == len(text) {
 			cx = x + 1
 		}
-		gl.LineWidth(2)
-		gc.MoveTo(cx, y-gc.GetFontSize()-1)
-		gc.LineTo(cx, y)
-		gc.Stroke()
-		gl.LineWidth(1)
+		drawCaret(gc, cx, y)
 	}
 	if last_i > 1 {
 		c.iEdge = last_i + c.iOffset
@@ -113,38 +115,201 @@ func fillStringAtWidthCursor(_gc draw2d.GraphicContext, c *Cursor, x, y, width f
 	}
 }
 
+// drawCaret draws the text cursor at x, on a line of text drawn at y
+func drawCaret(gc draw2d.GraphicContext, x, y float64) {
+	gc.SetLineWidth(2)
+	gc.BeginPath()
+	gc.MoveTo(x, y-gc.GetFontSize()-1)
+	gc.LineTo(x, y)
+	gc.Stroke()
+	gc.SetLineWidth(1)
+}
+
 // TODO calculate iEdge & iOffset using something like MoveToX
 type Cursor struct {
 	text              string   // text is the text stored in the field
 	textLines         []string // textLines is the text stored in the field, stored as lines
 	i, iOffset, iEdge int      // i is the position of the text cursor
-	iY, maxLines      int      // iY is the y position of i, maxLines is the max visible lines
+	anchor            int      // anchor is where the selection started, the text between it and i is selected
+	lineStarts        []int    // lineStarts holds the index in text each of textLines starts at
+	iY, maxLines      int      // iY is how many lines the text is scrolled up, maxLines is the max visible lines
 	lastBlink         time.Time
+	clock             draw2dui.Clock // clock tells the time for blinking
 	drawCursor        bool
+	history           history // history holds the text from before and after edits, for undoing and redoing
+}
+
+// defaultHistoryLength is how many edits text widgets can undo by default
+const defaultHistoryLength = 100
+
+// newCursor creates a Cursor holding text
+func newCursor(text string) *Cursor {
+	return &Cursor{text: text, clock: draw2dui.RealClock(), history: history{length: defaultHistoryLength}}
+}
+
+// snapshot is a Cursor's text and selection at some point in its history
+type snapshot struct {
+	text      string
+	i, anchor int
+}
+
+// history is a Cursor's undo and redo stacks
+type history struct {
+	undo, redo []snapshot
+	length     int  // length is how many snapshots undo holds at most
+	typing     bool // typing is set while typed characters are grouped into the last undo step
+	typedTo    int  // typedTo is where the text cursor was left by the last typed character
+}
+
+// push adds s to h's undo stack, forgetting the oldest snapshot if it's full, and clears the redo stack
+func (h *history) push(s snapshot) {
+	h.typing = false
+	h.redo = h.redo[:0]
+	if h.length == 0 {
+		return
+	}
+	if len(h.undo) >= h.length {
+		h.undo = append(h.undo[:0], h.undo[len(h.undo)-h.length+1:]...)
+	}
+	h.undo = append(h.undo, s)
+}
+
+// SetHistoryLength sets how many edits can be undone, 0 turns undo off. If more edits are already remembered,
+// the oldest ones are forgotten.
+func (c *Cursor) SetHistoryLength(length int) {
+	if length < 0 {
+		length = 0
+	}
+	c.history.length = length
+	if n := len(c.history.undo); n > length {
+		c.history.undo = append(c.history.undo[:0], c.history.undo[n-length:]...)
+	}
+}
+
+func (c *Cursor) snapshot() snapshot {
+	return snapshot{c.text, c.i, c.anchor}
+}
+
+// restore puts c's text and selection back to s, clamped onto its characters
+func (c *Cursor) restore(s snapshot) {
+	c.text = s.text
+	if c.iOffset > len(c.text) {
+		c.iOffset = 0
+	}
+	c.clamp()
+	c.MoveToOffset(s.i)
+	c.anchor = s.anchor
+	c.clamp()
+	c.drawCursor = true
+	c.history.typing = false
+}
+
+// clamp moves the text cursor, selection and scroll offset back inside of c's text after it was replaced,
+// onto the start of any characters they fell inside of
+func (c *Cursor) clamp() {
+	c.i, c.anchor = charStart(c.text, c.i), charStart(c.text, c.anchor)
+	if c.iOffset > c.i {
+		c.iOffset = c.i
+	}
+	c.iOffset = charStart(c.text, c.iOffset)
+}
+
+// MoveToOffset moves the text cursor to byte offset i in c's text, or the start of the character i falls
+// inside of. i is clamped to the text.
+func (c *Cursor) MoveToOffset(i int) bool {
+	if i < 0 {
+		i = 0
+	}
+	return c.MoveTo(charStart(c.text, i))
 }
 
-func (c *Cursor) GenLines(_gc draw2d.GraphicContext, width float64) {
-	gc := _gc.(*draw2dgl.GraphicContext)
+// Edit runs f, which edits c's text and returns whether it changed, so it can be undone in one step
+func (c *Cursor) Edit(f func() bool) bool {
+	before := c.snapshot()
+	if !f() {
+		return false
+	}
+	c.history.push(before)
+	return true
+}
+
+// Type inserts s over the selection. Characters typed one after another are undone in one step.
+func (c *Cursor) Type(s string) {
+	h := &c.history
+	if !h.typing || c.i != h.typedTo || c.anchor != c.i {
+		h.push(c.snapshot())
+	}
+	c.DeleteSelection()
+	c.Insert(s)
+	h.typing, h.typedTo = true, c.i
+}
+
+// Undo puts c's text back to how it was before the last edit, returning false if there's nothing to undo
+func (c *Cursor) Undo() bool {
+	h := &c.history
+	if len(h.undo) == 0 {
+		return false
+	}
+	h.redo = append(h.redo, c.snapshot())
+	s := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	c.restore(s)
+	return true
+}
+
+// Redo redoes the last edit undone, returning false if there's nothing to redo
+func (c *Cursor) Redo() bool {
+	h := &c.history
+	if len(h.redo) == 0 {
+		return false
+	}
+	h.undo = append(h.undo, c.snapshot())
+	s := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	c.restore(s)
+	return true
+}
+
+// historyKey handles the undo shortcuts: Ctrl+Z undoes, and Ctrl+Shift+Z or Ctrl+Y redo. Returns whether key
+// was a shortcut, and whether c's text changed.
+func (c *Cursor) historyKey(key draw2dui.Key, mods draw2dui.ModifierKey) (handled, changed bool) {
+	switch {
+	case key == draw2dui.KeyZ && mods == draw2dui.ModControl:
+		return true, c.Undo()
+	case key == draw2dui.KeyZ && mods == draw2dui.ModControl|draw2dui.ModShift,
+		key == draw2dui.KeyY && mods == draw2dui.ModControl:
+		return true, c.Redo()
+	}
+	return false, false
+}
+
+func (c *Cursor) GenLines(gc draw2d.GraphicContext, width float64) {
 	f, err := loadCurrentFont(gc)
 	if err != nil {
 		log.Println(err)
 		return
 	}
 	x := float64(3)
-	ii := 0
 	lastLine := 0
 	prev, hasPrev := truetype.Index(0), false
+	prevRune := rune(0)
 	fontName := gc.GetFontName()
 	c.textLines = make([]string, 0, 127)
+	c.lineStarts = make([]int, 0, 127)
 	for i, r := range c.text {
 		index := f.Index(r)
 		if hasPrev {
-			x += fUnitsToFloat64(f.Kern(fixed.Int26_6(gc.Current.Scale), prev, index))
+			x += fUnitsToFloat64(f.Kern(fontScale(gc), prev, index))
 		}
 		glyph := draw2dbase.FetchGlyph(gc, fontName, r)
-		if r == '\n' || x+glyph.Width > width {
-			c.textLines = append(c.textLines, string(c.text[lastLine:lastLine+ii]))
-			ii = 0
+		// Lines are only wrapped between characters, so accents stay with their letters
+		if r == '\n' || (x+glyph.Width > width && i > lastLine && !extendsChar(prevRune, r)) {
+			end := i
+			if r == '\n' && prevRune == '\r' && end > lastLine {
+				end-- // the \r of a \r\n ends the line with the \n, so the text cursor can't be put between them
+			}
+			c.textLines = append(c.textLines, c.text[lastLine:end])
+			c.lineStarts = append(c.lineStarts, lastLine)
 			x = 3
 			if r != '\n' {
 				lastLine = i
@@ -154,23 +319,33 @@ func (c *Cursor) GenLines(_gc draw2d.GraphicContext, width float64) {
 			prev, hasPrev = truetype.Index(0), false
 		}
 		if r != '\n' {
-			ii++
 			x += glyph.Width
 			prev, hasPrev = index, true
 		}
+		prevRune = r
 	}
-	if ii != 0 {
-		c.textLines = append(c.textLines, string(c.text[lastLine:lastLine+ii]))
-	}
+	// The last line is added even when it's empty, so the text cursor has a line to be on after a newline
+	c.textLines = append(c.textLines, c.text[lastLine:])
+	c.lineStarts = append(c.lineStarts, lastLine)
+}
+
+// blinkInterval is how long the text cursor is shown or hidden for when blinking
+const blinkInterval = time.Millisecond * 667
+
+// NextBlink returns when Blink next shows or hides the cursor
+func (c *Cursor) NextBlink() time.Time {
+	return c.lastBlink.Add(blinkInterval)
 }
 
 func (c *Cursor) Blink() (redraw bool) {
 	if c.i > c.iEdge {
-		c.iOffset += c.i - c.iEdge
+		for offset := c.iOffset + c.i - c.iEdge; c.iOffset < offset && c.iOffset < len(c.text); {
+			c.iOffset = nextBoundary(c.text, c.iOffset)
+		}
 		c.iEdge = c.i
 		redraw = true
 	}
-	if now := time.Now(); now.Sub(c.lastBlink) >= time.Millisecond*667 {
+	if now := c.clock.Now(); now.Sub(c.lastBlink) >= blinkInterval {
 		c.lastBlink = now
 		c.drawCursor = c.drawCursor == false
 		redraw = true
@@ -178,39 +353,187 @@ func (c *Cursor) Blink() (redraw bool) {
 	return
 }
 
+// Insert inserts s at the text cursor, moving the cursor past it
 func (c *Cursor) Insert(s string) {
+	i := c.i + len(s)
 	c.text = strings.Join([]string{c.text[:c.i], s, c.text[c.i:]}, "")
-	c.MoveRight()
+	c.MoveTo(i)
+	c.Deselect()
 }
 
-// GenLines must be called after
+// Selection returns the start and end of the selected text, which are equal when nothing is selected
+func (c *Cursor) Selection() (start, end int) {
+	start, end = c.anchor, c.i
+	if start > end {
+		start, end = end, start
+	}
+	if end > len(c.text) {
+		end = len(c.text)
+	}
+	if start > end {
+		start = end
+	}
+	return
+}
+
+// SelectedText returns the selected text
+func (c *Cursor) SelectedText() string {
+	start, end := c.Selection()
+	return c.text[start:end]
+}
+
+// Deselect clears the selection, leaving the text cursor where it is
+func (c *Cursor) Deselect() {
+	c.anchor = c.i
+}
+
+// SelectAll selects all of the text, moving the text cursor to its end. Returns false if it already was.
+func (c *Cursor) SelectAll() bool {
+	if c.anchor == 0 && c.i == len(c.text) {
+		return false
+	}
+	c.anchor = 0
+	c.MoveTo(len(c.text))
+	c.drawCursor = true
+	return true
+}
+
+// DeleteSelection deletes the selected text, returning false if nothing was selected
+func (c *Cursor) DeleteSelection() bool {
+	start, end := c.Selection()
+	if start == end {
+		return false
+	}
+	c.text = c.text[:start] + c.text[end:]
+	c.MoveTo(start)
+	c.Deselect()
+	c.drawCursor = true
+	return true
+}
+
+// Left moves the text cursor left, extending the selection with it if extend is set. Otherwise, a selection is
+// cleared with the cursor left at its start.
+func (c *Cursor) Left(extend bool) bool {
+	if start, end := c.Selection(); start < end && !extend {
+		c.MoveTo(start)
+		c.Deselect()
+		c.drawCursor = true
+		return true
+	}
+	moved := c.MoveLeft()
+	if !extend {
+		c.Deselect()
+	}
+	return moved
+}
+
+// Right moves the text cursor right, extending the selection with it if extend is set. Otherwise, a selection
+// is cleared with the cursor left at its end.
+func (c *Cursor) Right(extend bool) bool {
+	if start, end := c.Selection(); start < end && !extend {
+		c.MoveTo(end)
+		c.Deselect()
+		c.drawCursor = true
+		return true
+	}
+	moved := c.MoveRight()
+	if !extend {
+		c.Deselect()
+	}
+	return moved
+}
+
+// clipboardKey handles the clipboard shortcuts: Ctrl+C copies the selected text to cb, Ctrl+X cuts it, and
+// Ctrl+V pastes cb's text over the selection, cut short so c's text fits in maxlen. Copying and cutting do
+// nothing if nothing is selected. singleLine pastes newlines as spaces. Returns whether key was a shortcut,
+// and whether c's text changed.
+func (c *Cursor) clipboardKey(cb draw2dui.Clipboard, key draw2dui.Key, mods draw2dui.ModifierKey, maxlen int, singleLine bool) (handled, changed bool) {
+	if cb == nil || mods&^draw2dui.ModShift != draw2dui.ModControl {
+		return false, false
+	}
+	switch key {
+	default:
+		return false, false
+	case draw2dui.KeyC:
+		if start, end := c.Selection(); start < end {
+			cb.SetClipboardString(c.SelectedText())
+		}
+	case draw2dui.KeyX:
+		if start, end := c.Selection(); start < end {
+			cb.SetClipboardString(c.SelectedText())
+			return true, c.Edit(c.DeleteSelection)
+		}
+	case draw2dui.KeyV:
+		s := pasteable(cb.ClipboardString(), singleLine)
+		s = truncate(s, maxlen-charCount(c.text)+charCount(c.SelectedText()))
+		if s == "" {
+			return true, false
+		}
+		return true, c.Edit(func() bool {
+			c.DeleteSelection()
+			c.Insert(s)
+			return true
+		})
+	}
+	return true, false
+}
+
+// InsertLine adds s to the end of the text on a new line. GenLines must be called after.
 func (c *Cursor) InsertLine(s string) {
-	c.text = strings.Join(c.textLines, "\n") + "\n" + s
+	c.text = c.text + "\n" + s
 }
 
+// Line returns the index in textLines of the line the text cursor is on. A cursor between two lines which are
+// wrapped from one line of text is on the second.
+func (c *Cursor) Line() int {
+	if l := sort.SearchInts(c.lineStarts, c.i+1) - 1; l > 0 {
+		return l
+	}
+	return 0
+}
+
+// Backspace deletes the character before the text cursor, along with any accents or other marks on it
 func (c *Cursor) Backspace() bool {
 	if c.i == 0 {
 		return false
 	}
-	c.text = strings.Join([]string{c.text[:c.i-1], c.text[c.i:]}, "")
-	if c.iOffset > 0 && len(c.text)+1 == c.iEdge {
-		c.i--
-		c.iEdge--
-		c.iOffset--
-		c.drawCursor = true
-	} else {
-		c.MoveLeft()
+	prev := prevBoundary(c.text, c.i)
+	n := c.i - prev
+	c.text = strings.Join([]string{c.text[:prev], c.text[c.i:]}, "")
+	c.i = prev
+	if c.iOffset > 0 && len(c.text)+n == c.iEdge {
+		c.iEdge -= n
+		if c.iOffset > prev {
+			c.iOffset = prev
+		}
+		c.iOffset = prevBoundary(c.text, c.iOffset)
+	} else if c.i < c.iOffset {
+		c.iEdge -= n
+		c.iOffset = c.i
 	}
+	c.drawCursor = true
+	c.Deselect()
 	return true
 }
 
-// TODO Make MoveLeft and MoveRight take a parameter
+// Delete deletes the character after the text cursor, along with any accents or other marks on it
+func (c *Cursor) Delete() bool {
+	if c.i >= len(c.text) {
+		return false
+	}
+	c.text = strings.Join([]string{c.text[:c.i], c.text[nextBoundary(c.text, c.i):]}, "")
+	c.drawCursor = true
+	c.Deselect()
+	return true
+}
+
+// MoveLeft moves the text cursor left by a character, along with any accents or other marks on it
 func (c *Cursor) MoveLeft() bool {
 	if c.i > 0 {
-		c.i--
-		if c.i < c.iOffset {
-			c.iEdge--
-			c.iOffset--
+		prev := prevBoundary(c.text, c.i)
+		if c.i = prev; c.i < c.iOffset {
+			c.iEdge -= c.iOffset - prev
+			c.iOffset = prev
 		}
 		c.drawCursor = true
 		return true
@@ -218,12 +541,14 @@ func (c *Cursor) MoveLeft() bool {
 	return false
 }
 
+// MoveRight moves the text cursor right by a character, along with any accents or other marks on it
 func (c *Cursor) MoveRight() bool {
 	if c.i < len(c.text) {
-		c.i++
-		if c.i > c.iEdge {
-			c.iEdge++
-			c.iOffset++
+		next := nextBoundary(c.text, c.i)
+		n := next - c.i
+		if c.i = next; c.i > c.iEdge {
+			c.iEdge += n
+			c.iOffset = nextBoundary(c.text, c.iOffset)
 		}
 		c.drawCursor = true
 		return true
@@ -250,29 +575,219 @@ func (c *Cursor) MoveTo(i int) bool {
 	return false
 }
 
-func (c *Cursor) MoveToX(_gc draw2d.GraphicContext, x, mx, width float64) {
-	gc := _gc.(*draw2dgl.GraphicContext)
+// MoveToX moves the text cursor to the character drawn at mx, for text drawn from x and cut off at width
+func (c *Cursor) MoveToX(gc draw2d.GraphicContext, x, mx, width float64) {
+	c.i = c.iOffset + indexAtX(gc, c.text[c.iOffset:], x, mx, width)
+}
+
+// indexAtX returns the index of the character in text drawn at mx, for text drawn from x and cut off at width.
+// Characters are measured along with any accents or other marks on them.
+func indexAtX(gc draw2d.GraphicContext, text string, x, mx, width float64) int {
 	f, err := loadCurrentFont(gc)
 	if err != nil {
 		log.Println(err)
-		return
+		return 0
 	}
 	prev, hasPrev := truetype.Index(0), false
 	width += x
 	fontName := gc.GetFontName()
-	text := c.text[c.iOffset:]
-	for i, r := range text {
+	for i := 0; i < len(text); {
+		next := nextBoundary(text, i)
+		charWidth := 0.0
+		for _, r := range text[i:next] {
+			index := f.Index(r)
+			if hasPrev {
+				charWidth += fUnitsToFloat64(f.Kern(fontScale(gc), prev, index))
+			}
+			charWidth += draw2dbase.FetchGlyph(gc, fontName, r).Width
+			prev, hasPrev = index, true
+		}
+		if x+charWidth > mx || x+charWidth > width {
+			return i
+		}
+		x += charWidth
+		i = next
+	}
+	return len(text)
+}
+
+// stringWidth returns how wide text is when drawn with gc
+func stringWidth(gc draw2d.GraphicContext, text string) float64 {
+	f, err := loadCurrentFont(gc)
+	if err != nil {
+		log.Println(err)
+		return 0
+	}
+	x := 0.0
+	prev, hasPrev := truetype.Index(0), false
+	fontName := gc.GetFontName()
+	for _, r := range text {
 		index := f.Index(r)
 		if hasPrev {
-			x += fUnitsToFloat64(f.Kern(fixed.Int26_6(gc.Current.Scale), prev, index))
-		}
-		glyph := draw2dbase.FetchGlyph(gc, fontName, r)
-		if x+glyph.Width > mx || x+glyph.Width > width {
-			c.i = i + c.iOffset
-			return
+			x += fUnitsToFloat64(f.Kern(fontScale(gc), prev, index))
 		}
-		x += glyph.Width
+		x += draw2dbase.FetchGlyph(gc, fontName, r).Width
 		prev, hasPrev = index, true
 	}
-	c.i = len(text) + c.iOffset
+	return x
+}
+
+// fillSelection fills the background of text[start:end] with theme's Selection color, for text drawn at x, y
+// and cut off at width
+func fillSelection(gc draw2d.GraphicContext, theme *draw2dui.Theme, text string, start, end int, x, y, width float64) {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(text) {
+		end = len(text)
+	}
+	if start >= end {
+		return
+	}
+	x0, x1 := x+stringWidth(gc, text[:start]), math.Min(x+stringWidth(gc, text[:end]), x+width)
+	if x0 >= x1 {
+		return
+	}
+	gc.Save()
+	gc.SetFillColor(theme.Selection)
+	gc.BeginPath()
+	draw2dkit.Rectangle(gc, x0, y-gc.GetFontSize(), x1, y+theme.LineSpacing)
+	gc.Fill()
+	gc.Restore()
+}
+
+// extendsChar returns whether r is drawn as part of the character before it, which ends with prev. Accents
+// and other combining marks, emoji modifiers and characters joined by zero width joiners are, and so is the
+// \n of a \r\n. Nothing else is joined to either side of a line break.
+func extendsChar(prev, r rune) bool {
+	switch {
+	case prev == '\r':
+		return r == '\n'
+	case prev == '\n', r == '\r', r == '\n':
+		return false
+	case prev == '\u200d':
+		return true
+	}
+	return isExtender(r)
+}
+
+// isExtender returns whether r is drawn as part of any character before it other than a line break
+func isExtender(r rune) bool {
+	switch {
+	case r == '\u200d':
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff, r >= 0xe0020 && r <= 0xe007f: // emoji modifiers and tags
+		return true
+	}
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// nextBoundary returns the index in s of the character after the one at i, which is the end of s if there
+// isn't one. Characters are grapheme clusters, such as a letter and the accents on it, or a pair of regional
+// indicators drawn as a flag.
+func nextBoundary(s string, i int) int {
+	if i >= len(s) {
+		return len(s)
+	}
+	r, n := utf8.DecodeRuneInString(s[i:])
+	i += n
+	if isRegionalIndicator(r) {
+		if next, n := utf8.DecodeRuneInString(s[i:]); isRegionalIndicator(next) {
+			r = next
+			i += n
+		}
+	}
+	for i < len(s) {
+		next, n := utf8.DecodeRuneInString(s[i:])
+		if !extendsChar(r, next) {
+			break
+		}
+		r = next
+		i += n
+	}
+	return i
+}
+
+// safeBoundary returns the closest index in s at or before i which is certainly the start of a character. It
+// only looks back over runes which could be part of the character before them, so it's cheap on long lines.
+func safeBoundary(s string, i int) int {
+	for i > 0 && i < len(s) && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	for i > 0 && i < len(s) {
+		r, _ := utf8.DecodeRuneInString(s[i:])
+		prev, n := utf8.DecodeLastRuneInString(s[:i])
+		if prev == '\n' || (prev != '\u200d' && prev != '\r' && !isExtender(r) && !isRegionalIndicator(r)) {
+			return i
+		}
+		i -= n
+	}
+	return i
+}
+
+// charStart returns the index in s of the start of the character containing i, see nextBoundary
+func charStart(s string, i int) int {
+	if i >= len(s) {
+		return len(s)
+	}
+	start := safeBoundary(s, i)
+	for {
+		next := nextBoundary(s, start)
+		if next > i {
+			return start
+		}
+		start = next
+	}
+}
+
+// prevBoundary returns the index in s of the character before i, see nextBoundary
+func prevBoundary(s string, i int) int {
+	if i > len(s) {
+		return len(s)
+	} else if i <= 0 {
+		return 0
+	}
+	return charStart(s, i-1)
+}
+
+// charCount returns how many characters s holds, see nextBoundary
+func charCount(s string) int {
+	n := 0
+	for i := 0; i < len(s); i = nextBoundary(s, i) {
+		n++
+	}
+	return n
+}
+
+// truncate cuts s short to at most n characters, see nextBoundary
+func truncate(s string, n int) string {
+	i := 0
+	for ; n > 0 && i < len(s); n-- {
+		i = nextBoundary(s, i)
+	}
+	return s[:i]
+}
+
+// typeable returns whether r can be typed into text widgets, which excludes control characters
+func typeable(r rune) bool {
+	return utf8.ValidRune(r) && !unicode.IsControl(r)
+}
+
+// pasteable returns s with the characters which can't be typed removed. Line breaks become '\n', or spaces if
+// singleLine is true.
+func pasteable(s string, singleLine bool) string {
+	s = strings.Replace(strings.Replace(s, "\r\n", "\n", -1), "\r", "\n", -1)
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' && singleLine:
+			return ' '
+		case r == '\n' || typeable(r):
+			return r
+		}
+		return -1
+	}, s)
 }
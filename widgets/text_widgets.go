@@ -2,64 +2,77 @@
 package widgets
 
 import (
-	"github.com/go-gl/gl/v2.1/gl"
-	"github.com/go-gl/glfw/v3.1/glfw"
 	"github.com/llgcode/draw2d"
 	"github.com/llgcode/draw2d/draw2dkit"
-	"github.com/redstarcoder/draw2dglkit"
 	"github.com/redstarcoder/draw2dui"
-	"image/color"
+	"io/ioutil"
+	"log"
+	"math"
 	"strings"
+	"time"
 	"unicode/utf8"
 )
 
-// TODO text highlighting
+// scrollLines is how many lines text widgets scroll for each step of a mouse wheel
+const scrollLines = 3
+
+// TextBox is a widget for editing multiple lines of wrapped text
 type TextBox struct {
+	draw2dui.Handlers
 	cursor                     *Cursor
 	x, y, width, height        float64
+	scrollFrac                 float64 // scrollFrac is how many pixels the text is scrolled up past cursor.iY lines
+	column                     float64 // column is the x position Up and Down keep the text cursor near
+	keepColumn                 bool    // keepColumn is set while column is kept by moving up and down
 	maxlen                     int
 	enabled, redraw, hasCursor bool
+	dropTarget                 bool // dropTarget is set while files are dragged over the widget
+	dragging                   bool // dragging is set while text is being selected with the mouse
 	shape                      *draw2d.Path
-	window, offscreen          *glfw.Window
+	window                     draw2dui.CursorSetter
 	gc                         *draw2d.GraphicContext // gc may be overwritten so it's a pointer to an interface
+	clipboard                  draw2dui.Clipboard
+	theme                      *draw2dui.Theme
 	name                       string
 }
 
-// NewTextBox creates a new TextBox widget
-// BUG(x) TextBox does not support enabled state
-// BUG(x) TextBox default is disabled, should be enabled
+// NewTextBox creates a new TextBox widget. window may be nil to run headless.
 // BUG(x) TextBox should have a scrollbar
 // BUG(x) TextBox text wrapping should be optional
-func NewTextBox(gc *draw2d.GraphicContext, window, offscreen *glfw.Window, x, y, width, height float64, text string) *TextBox {
+func NewTextBox(gc *draw2d.GraphicContext, window draw2dui.CursorSetter, x, y, width, height float64, text string) *TextBox {
 	textBox := &TextBox{
-		cursor:    &Cursor{text: text},
-		gc:        gc,
-		window:    window,
-		offscreen: offscreen,
-		x:         x,
-		y:         y,
-		width:     width,
-		height:    height,
-		maxlen:    0x7ffffffe,
-		//		enabled:   true,
-		shape:  &draw2d.Path{},
-		redraw: true,
-		name:   draw2dui.NameWidget("TextBox"),
+		cursor:  newCursor(text),
+		gc:      gc,
+		window:  window,
+		x:       x,
+		y:       y,
+		width:   width,
+		height:  height,
+		maxlen:  0x7ffffffe,
+		enabled: true,
+		shape:   &draw2d.Path{},
+		redraw:  true,
+		theme:   defaultTheme,
+		name:    draw2dui.NameWidget("TextBox"),
 	}
 	textBox.cursor.GenLines(*gc, width)
 	textBox.reshape()
 	return textBox
 }
 
+// InsertLine adds s to the end of tb's text on a new line
 func (tb *TextBox) InsertLine(s string) {
-	tb.cursor.InsertLine(s)
+	tb.cursor.Edit(func() bool {
+		tb.cursor.InsertLine(s)
+		return true
+	})
 	tb.cursor.GenLines(*tb.gc, tb.width)
 }
 
 // reshape recreates tf's path, which is used for drawing it to the screen
 func (tb *TextBox) reshape() {
 	tb.shape = &draw2d.Path{}
-	tb.cursor.maxLines = int((tb.height - 2) / ((*tb.gc).GetFontSize() + 3))
+	tb.cursor.maxLines = int((tb.height - tb.theme.BorderWidth*2) / tb.theme.LineHeight(*tb.gc))
 	draw2dkit.Rectangle(tb.shape, tb.x, tb.y, tb.x+tb.width-1, tb.y+tb.height-1)
 	tb.redraw = true
 }
@@ -69,22 +82,45 @@ func (tb *TextBox) Name() string {
 	return tb.name
 }
 
+// NeedsRedraw returns true if tb changed since it was last drawn
+func (tb *TextBox) NeedsRedraw() bool {
+	return tb.redraw
+}
+
 // Draw draws the widget, selected determines if the widget displays as selected or not, and forceRedraw
 // forces a full redraw of the widget.
 func (tb *TextBox) Draw(selected, forceRedraw bool) {
 	if tb.redraw || forceRedraw {
 		gc := *tb.gc
 		gc.Save()
-		tb.clear(gc, false)
-		gl.LineWidth(1)
-		gc.SetFillColor(color.RGBA{255, 255, 255, 0xff})
-		gc.SetStrokeColor(color.RGBA{0, 0, 0, 0xff})
+		gc.SetLineWidth(tb.theme.BorderWidth)
+		gc.SetFillColor(backgroundColor(tb.theme, tb.dropTarget))
+		gc.SetStrokeColor(borderColor(tb.theme, selected || tb.dropTarget, tb.enabled))
 		gc.FillStroke(tb.shape)
-		gc.SetFillColor(color.RGBA{0, 0, 0, 0xff})
-		y := tb.y + float64(tb.cursor.maxLines)*(gc.GetFontSize()+3)
-		for i := 0; i < tb.cursor.maxLines && i+tb.cursor.iY < len(tb.cursor.textLines); i++ {
-			gc.FillStringAt(tb.cursor.textLines[len(tb.cursor.textLines)-1-i-tb.cursor.iY], tb.x+1, y)
-			y -= gc.GetFontSize() + 3
+		gc.SetFillColor(textColor(tb.theme, tb.enabled))
+		gc.SetStrokeColor(textColor(tb.theme, tb.enabled))
+		lineHeight := tb.theme.LineHeight(gc)
+		lines, frac := tb.cursor.maxLines, tb.scrollFrac
+		if clipper, ok := gc.(draw2dui.Clipper); ok {
+			clipper.ClipRect(tb.x, tb.y+tb.theme.BorderWidth, tb.x+tb.width, tb.y+tb.height-tb.theme.BorderWidth)
+			if frac > 0 {
+				lines++ // the top line is partly scrolled into view
+			}
+		} else {
+			frac = 0 // lines can't be drawn partly, so they're only drawn where they fit whole
+		}
+		x, y := tb.x+tb.theme.TextPadding, tb.y+float64(tb.cursor.maxLines)*lineHeight+frac
+		start, end := tb.cursor.Selection()
+		caret := tb.cursor.Line()
+		for i := 0; i < lines && i+tb.cursor.iY < len(tb.cursor.textLines); i++ {
+			l := len(tb.cursor.textLines) - 1 - i - tb.cursor.iY
+			line, lineStart := tb.cursor.textLines[l], tb.cursor.lineStarts[l]
+			fillSelection(gc, tb.theme, line, start-lineStart, end-lineStart, x, y, tb.width-tb.theme.TextPadding*2)
+			gc.FillStringAt(line, x, y)
+			if l == caret && selected && tb.cursor.drawCursor {
+				drawCaret(gc, x+stringWidth(gc, line[:tb.cursor.i-lineStart])+1, y)
+			}
+			y -= lineHeight
 		}
 		gc.Restore()
 
@@ -92,23 +128,37 @@ func (tb *TextBox) Draw(selected, forceRedraw bool) {
 	}
 }
 
-// clear clears tf's border to make redrawing look more consistent without a complete screen clear. If fill
-// is true, it also fills the shape with white.
-func (tb *TextBox) clear(gc draw2d.GraphicContext, fill bool) {
-	gl.LineWidth(3)
-	if !fill {
-		gc.SetStrokeColor(color.RGBA{255, 255, 255, 0xff})
-		gc.Stroke(tb.shape)
-	} else {
-		gc.Save()
-		gc.SetStrokeColor(color.RGBA{255, 255, 255, 0xff})
-		gc.SetFillColor(color.RGBA{255, 255, 255, 0xff})
-		gc.FillStroke(tb.shape)
-		gc.Restore()
+// SetTheme restyles tb with t
+func (tb *TextBox) SetTheme(t *draw2dui.Theme) {
+	tb.theme = t
+	tb.reshape()
+	tb.cursor.GenLines(*tb.gc, tb.width)
+}
+
+// SetClock sets the Clock tb's cursor blinks with
+func (tb *TextBox) SetClock(c draw2dui.Clock) {
+	tb.cursor.clock = c
+}
+
+// SetHistoryLength sets how many edits to tb can be undone, 0 turns undo off. It's 100 by default.
+func (tb *TextBox) SetHistoryLength(length int) {
+	tb.cursor.SetHistoryLength(length)
+}
+
+// SetClipboard sets the Clipboard tb copies to and pastes from
+func (tb *TextBox) SetClipboard(c draw2dui.Clipboard) {
+	tb.clipboard = c
+}
+
+// NextDeadline returns when tb's cursor next blinks while it's selected
+func (tb *TextBox) NextDeadline(selected bool) (time.Time, bool) {
+	if !selected {
+		return time.Time{}, false
 	}
+	return tb.cursor.NextBlink(), true
 }
 
-// Handle processes tf's cursor
+// Handle processes tb's cursor
 func (tb *TextBox) Handle(selected bool) bool {
 	if selected {
 		if tb.cursor.Blink() {
@@ -119,73 +169,270 @@ func (tb *TextBox) Handle(selected bool) bool {
 	return false
 }
 
-// KeyPress has the widget process a KeyPress event
-func (tb *TextBox) KeyPress(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) draw2dui.Event {
-	if action == glfw.Release {
+// KeyPress has the widget process a KeyPress event. The arrow keys move the text cursor, and with Shift they
+// select text. Enter starts a new line. Ctrl+A selects all of the text. Ctrl+C and Ctrl+X copy and cut the
+// selection, doing nothing if nothing is selected, and Ctrl+V pastes over the selection. Ctrl+Z undoes edits,
+// and Ctrl+Shift+Z or Ctrl+Y redo them. tb is scrolled to keep the text cursor in view.
+func (tb *TextBox) KeyPress(key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if action == draw2dui.Release {
 		return draw2dui.EventNone
 	}
+	if key != draw2dui.KeyUp && key != draw2dui.KeyDown {
+		tb.keepColumn = false
+	}
+	event := tb.keyPress(key, mods)
+	if event == draw2dui.EventAction {
+		tb.showCursor()
+	}
+	return event
+}
+
+// keyPress has tb process a key being pressed or repeated
+func (tb *TextBox) keyPress(key draw2dui.Key, mods draw2dui.ModifierKey) draw2dui.Event {
+	if handled, changed := tb.cursor.historyKey(key, mods); handled {
+		if !changed {
+			return draw2dui.EventNone
+		}
+		return tb.changed()
+	}
+	if handled, changed := tb.cursor.clipboardKey(tb.clipboard, key, mods, tb.maxlen, false); handled {
+		if !changed {
+			return draw2dui.EventNone
+		}
+		return tb.changed()
+	}
+	extend := mods&draw2dui.ModShift != 0
 	switch key {
 	default:
 		return draw2dui.EventNone
-	/*case glfw.KeyLeft:
-		if tb.cursor.MoveLeft() {
+	case draw2dui.KeyLeft:
+		if tb.cursor.Left(extend) {
 			tb.redraw = true
 			return draw2dui.EventAction
 		}
-	case glfw.KeyRight:
-		if tb.cursor.MoveRight() {
+	case draw2dui.KeyRight:
+		if tb.cursor.Right(extend) {
 			tb.redraw = true
 			return draw2dui.EventAction
 		}
-	case glfw.KeyBackspace:
-		if tb.cursor.Backspace() {
+	case draw2dui.KeyUp:
+		if tb.moveLines(-1, extend) {
 			tb.redraw = true
 			return draw2dui.EventAction
 		}
-	case glfw.KeyEnter:
-		return draw2dui.EventConfirm*/
-	case glfw.KeyUp:
-		if tb.cursor.iY < len(tb.cursor.textLines)-1 {
-			tb.cursor.iY++
+	case draw2dui.KeyDown:
+		if tb.moveLines(1, extend) {
 			tb.redraw = true
 			return draw2dui.EventAction
 		}
-	case glfw.KeyDown:
-		if tb.cursor.iY > 0 {
-			tb.cursor.iY--
-			tb.redraw = true
+	case draw2dui.KeyBackspace:
+		if tb.cursor.Edit(func() bool { return tb.cursor.DeleteSelection() || tb.cursor.Backspace() }) {
+			return tb.changed()
+		}
+	case draw2dui.KeyDelete:
+		if tb.cursor.Edit(func() bool { return tb.cursor.DeleteSelection() || tb.cursor.Delete() }) {
+			return tb.changed()
+		}
+	case draw2dui.KeyEnter:
+		if tb.typeText("\n") {
 			return draw2dui.EventAction
 		}
+	case draw2dui.KeyA:
+		if mods&draw2dui.ModControl != 0 && tb.cursor.SelectAll() {
+			tb.redraw = true
+		}
 	}
 	return draw2dui.EventNone
 }
 
-// CharPress adds a character to the TextBox
-func (tb *TextBox) CharPress(char rune) draw2dui.Event {
-	if !utf8.ValidRune(char) || len(tb.GetString()) >= tb.maxlen {
+// changed rewraps tb's text after it was edited
+func (tb *TextBox) changed() draw2dui.Event {
+	tb.cursor.GenLines(*tb.gc, tb.width)
+	tb.redraw = true
+	return draw2dui.EventAction
+}
+
+// typeText types s over tb's selection, returning false if the text wouldn't fit in tb.maxlen
+func (tb *TextBox) typeText(s string) bool {
+	text := tb.cursor.text
+	start, end := tb.cursor.Selection()
+	if charCount(text[:start]+s+text[end:]) > tb.maxlen {
+		return false
+	}
+	tb.cursor.Type(s)
+	tb.changed()
+	return true
+}
+
+// moveLines moves tb's text cursor down by lines, or up if lines is negative, keeping it as close as it can
+// to the column it was in before it started moving up and down. Moving past the first or last line moves it
+// to the start or end of the text. extend extends the selection with it, otherwise the selection is cleared.
+func (tb *TextBox) moveLines(lines int, extend bool) bool {
+	c := tb.cursor
+	if len(c.textLines) == 0 {
+		return false // the text couldn't be laid out without a font
+	}
+	l := c.Line()
+	if !tb.keepColumn {
+		tb.column = stringWidth(*tb.gc, c.textLines[l][:c.i-c.lineStarts[l]])
+		tb.keepColumn = true
+	}
+	i := len(c.text)
+	if l += lines; l < 0 {
+		i = 0
+	} else if l < len(c.textLines) {
+		i = tb.lineIndex(l, tb.column)
+	}
+	moved := i != c.i || (!extend && c.anchor != c.i)
+	c.i = i
+	c.drawCursor = true
+	if !extend {
+		c.Deselect()
+	}
+	return moved
+}
+
+// lineIndex returns the index in tb's text of the character on line l drawn at x, measured from the line's
+// start
+func (tb *TextBox) lineIndex(l int, x float64) int {
+	c := tb.cursor
+	i := c.lineStarts[l] + indexAtX(*tb.gc, c.textLines[l], 0, x, math.Inf(1))
+	if l+1 < len(c.lineStarts) && i >= c.lineStarts[l+1] {
+		// The line was wrapped, and its end is the start of the next line
+		i = prevBoundary(c.text, c.lineStarts[l+1])
+	}
+	return i
+}
+
+// showCursor scrolls tb so the line its text cursor is on is in view
+func (tb *TextBox) showCursor() {
+	if len(tb.cursor.textLines) == 0 {
+		return // the text couldn't be laid out without a font
+	}
+	row := len(tb.cursor.textLines) - 1 - tb.cursor.Line() // row counts lines up from the bottom
+	iY, maxLines := tb.cursor.iY, tb.cursor.maxLines
+	if maxLines < 1 {
+		maxLines = 1
+	}
+	if row < iY {
+		iY = row
+	} else if row >= iY+maxLines {
+		iY = row - maxLines + 1
+	}
+	if iY != tb.cursor.iY {
+		tb.cursor.iY = iY
+		tb.scrollFrac = 0
+		tb.redraw = true
+	}
+}
+
+// Scroll scrolls tb's text by scrollLines lines for each step of yoff, positive yoff scrolls up. Fractions of
+// a step scroll smoothly if the draw2d.GraphicContext implements draw2dui.Clipper.
+func (tb *TextBox) Scroll(xoff, yoff float64) draw2dui.Event {
+	lineHeight := tb.theme.LineHeight(*tb.gc)
+	old := float64(tb.cursor.iY)*lineHeight + tb.scrollFrac
+	max := float64(len(tb.cursor.textLines)-1) * lineHeight
+	pos := math.Max(0, math.Min(max, old+yoff*scrollLines*lineHeight))
+	if pos == old {
 		return draw2dui.EventNone
 	}
-	tb.cursor.Insert(string(char))
+	tb.cursor.iY = int(math.Floor(pos/lineHeight + 1e-9))
+	tb.scrollFrac = math.Max(0, pos-float64(tb.cursor.iY)*lineHeight)
 	tb.redraw = true
 	return draw2dui.EventAction
 }
 
-// MMove has the widget process a MouseMove event
+// DragOver highlights tb while files are dragged over it
+func (tb *TextBox) DragOver(xpos, ypos float64, paths []string) bool {
+	if len(paths) == 0 {
+		return false
+	}
+	if !tb.dropTarget {
+		tb.dropTarget = true
+		tb.redraw = true
+	}
+	return true
+}
+
+// DragLeave removes tb's highlight
+func (tb *TextBox) DragLeave() {
+	if tb.dropTarget {
+		tb.dropTarget = false
+		tb.redraw = true
+	}
+}
+
+// Drop inserts the contents of the files with paths over tb's selection, as long as they're text and fit in
+// tb.maxlen. Files which can't be read are logged and skipped.
+func (tb *TextBox) Drop(xpos, ypos float64, paths []string) draw2dui.Event {
+	tb.DragLeave()
+	var contents []string
+	size := charCount(tb.cursor.text) - charCount(tb.cursor.SelectedText())
+	for _, path := range paths {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		if !utf8.Valid(b) || size+charCount(string(b)) > tb.maxlen {
+			continue
+		}
+		contents = append(contents, string(b))
+		size += charCount(string(b))
+	}
+	if len(contents) == 0 {
+		return draw2dui.EventNone
+	}
+	inserted := strings.Join(contents, "")
+	tb.cursor.Edit(func() bool {
+		tb.cursor.DeleteSelection()
+		tb.cursor.Insert(inserted)
+		return true
+	})
+	tb.changed()
+	tb.keepColumn = false
+	tb.showCursor()
+	return draw2dui.EventAction
+}
+
+// CharPress adds a character to the TextBox, replacing the selected text
+func (tb *TextBox) CharPress(char rune) draw2dui.Event {
+	if !typeable(char) || !tb.typeText(string(char)) {
+		return draw2dui.EventNone
+	}
+	tb.keepColumn = false
+	tb.showCursor()
+	return draw2dui.EventAction
+}
+
+// MMove has the widget process a MouseMove event, selecting text while the mouse is dragged
 func (tb *TextBox) MMove(xpos, ypos float64) draw2dui.Event {
+	if tb.dragging && !math.IsInf(xpos, 0) {
+		if i := tb.indexAt(xpos, ypos); i != tb.cursor.i {
+			tb.cursor.i = i
+			tb.redraw = true
+		}
+		return draw2dui.EventHasCursor
+	}
 	if !tb.IsInside(xpos, ypos) {
 		tb.hasCursor = false
 		return draw2dui.EventNone
 	}
 	if !tb.hasCursor {
-		tb.window.SetCursor(glfw.CreateStandardCursor(int(glfw.IBeamCursor)))
+		setCursor(tb.window, draw2dui.IBeamCursor)
 		tb.hasCursor = true
 	}
 	return draw2dui.EventHasCursor
 }
 
-// MClick has the widget process a MouseClick event
-func (tb *TextBox) MClick(xpos, ypos float64, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) draw2dui.Event {
-	if button == glfw.MouseButtonLeft && action == glfw.Press {
+// MClick has the widget process a MouseClick event. Pressing inside of tb moves the text cursor there, and
+// dragging selects text until the button is released. Shift extends the selection to where tb is clicked.
+func (tb *TextBox) MClick(xpos, ypos float64, button draw2dui.MouseButton, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if button == draw2dui.MouseButtonLeft && action == draw2dui.Release {
+		tb.dragging = false
+		return draw2dui.EventNone
+	}
+	if button == draw2dui.MouseButtonLeft && action == draw2dui.Press {
 		tb.redraw = true
 		if !tb.IsInside(xpos, ypos) {
 			return draw2dui.EventNone
@@ -194,13 +441,38 @@ func (tb *TextBox) MClick(xpos, ypos float64, button glfw.MouseButton, action gl
 		return draw2dui.EventNone
 	}
 	tb.cursor.drawCursor = false // gets swapped to true before next draw
-	tb.cursor.MoveToX(*tb.gc, tb.x, xpos, tb.width)
+	tb.cursor.i = tb.indexAt(xpos, ypos)
+	if mods&draw2dui.ModShift == 0 {
+		tb.cursor.Deselect()
+	}
+	tb.keepColumn = false
+	tb.dragging = true
 	return draw2dui.EventSelected
 }
 
+// indexAt returns the index in tb's text of the character drawn at xpos, ypos
+func (tb *TextBox) indexAt(xpos, ypos float64) int {
+	lines := tb.cursor.textLines
+	if len(lines) == 0 {
+		return 0
+	}
+	lineHeight := tb.theme.LineHeight(*tb.gc)
+	frac := tb.scrollFrac
+	if _, ok := (*tb.gc).(draw2dui.Clipper); !ok {
+		frac = 0
+	}
+	bottom := tb.y + float64(tb.cursor.maxLines)*lineHeight + frac + tb.theme.LineSpacing
+	l := len(lines) - 1 - tb.cursor.iY - int(math.Floor((bottom-ypos)/lineHeight))
+	if l < 0 {
+		return 0
+	} else if l >= len(lines) {
+		return len(tb.cursor.text)
+	}
+	return tb.lineIndex(l, xpos-tb.x-tb.theme.TextPadding)
+}
+
 // SetPos changes the widget's x, y coordinates
 func (tb *TextBox) SetPos(x, y float64) {
-	tb.clear(*tb.gc, true)
 	tb.x, tb.y = x, y
 	tb.reshape()
 }
@@ -212,10 +484,9 @@ func (tb *TextBox) GetPos() (float64, float64) {
 
 // SetDimensions sets tf's drawn width and height
 func (tb *TextBox) SetDimensions(w, h float64) {
-	tb.clear(*tb.gc, true)
 	tb.width, tb.height = w, h
 	tb.reshape()
-	tb.SetString(strings.Join(tb.cursor.textLines, "\n"))
+	tb.cursor.GenLines(*tb.gc, tb.width)
 }
 
 // GetDimensions returns tf's drawn width and height
@@ -223,56 +494,47 @@ func (tb *TextBox) GetDimensions() (float64, float64) {
 	return tb.width, tb.height
 }
 
-// IsInside checks if point x, y is inside of the widget's boundaries. It uses tb.offscreen as a pallet
+// IsInside checks if point x, y is inside of the widget's boundaries
 func (tb *TextBox) IsInside(x, y float64) bool {
-	return draw2dglkit.IsPointInShape(*tb.gc, tb.offscreen, x, y, tb.shape)
+	return draw2dui.IsPointInPath(tb.shape, x, y)
 }
 
-// SetString sets tf's text, using tb.maxlen as the max length
+// SetString sets tf's text, cut short to tb.maxlen characters. It can be undone like an edit.
 func (tb *TextBox) SetString(s string) {
-	if len(s) > tb.maxlen {
-		tb.cursor.text = s[:tb.maxlen]
-	} else {
+	s = truncate(s, tb.maxlen)
+	tb.cursor.Edit(func() bool {
+		changed := tb.cursor.text != s
 		tb.cursor.text = s
-	}
+		return changed
+	})
+	tb.cursor.clamp()
+	tb.cursor.Deselect()
 	tb.cursor.GenLines(*tb.gc, tb.width)
-	//if tb.GetInt() > len(tb.GetString()) {
-	//	tb.SetInt(len(tb.GetString()))
-	//}
 	tb.redraw = true
 }
 
 // GetString returns tf's text
-// BUG(x) needs to rebuild cursor.text from cursor.textLines
 func (tb *TextBox) GetString() string {
-	tb.cursor.text = strings.Join(tb.cursor.textLines, "\n")
 	return tb.cursor.text
 }
 
-// SetInt moves tf's text cursor
+// SetInt moves tb's text cursor to byte offset i in its text, clearing the selection. An offset inside of a
+// character moves it to the start of the character.
 func (tb *TextBox) SetInt(i int) {
-	if tb.cursor.MoveTo(i) {
+	if tb.cursor.MoveToOffset(i) || tb.cursor.anchor != tb.cursor.i {
 		tb.redraw = true
 	}
+	tb.cursor.Deselect()
 }
 
-// GetInt returns tf's text cursor's position
+// GetInt returns tb's text cursor's position as a byte offset in its text, so GetString()[:GetInt()] is the
+// text before it
 func (tb *TextBox) GetInt() int {
 	return tb.cursor.i
 }
 
-// SetData does nothing
-func (tb *TextBox) SetData(d interface{}) {
-}
-
-// GetData returns nil
-func (tb *TextBox) GetData() interface{} {
-	return nil
-}
-
 // SetEnabled enables or disables the widget
 func (tb *TextBox) SetEnabled(enabled bool) {
-	panic("not implemented.")
 	if tb.enabled != enabled {
 		tb.enabled = enabled
 		tb.redraw = true
@@ -284,36 +546,40 @@ func (tb *TextBox) GetEnabled() bool {
 	return tb.enabled
 }
 
-// TODO text highlighting
+// TextField is a widget for editing a single line of text
 type TextField struct {
+	draw2dui.Handlers
 	cursor                     *Cursor
 	x, y, width, height        float64
 	maxlen                     int
 	enabled, redraw, hasCursor bool
+	dropTarget                 bool // dropTarget is set while files are dragged over the widget
+	dragging                   bool // dragging is set while text is being selected with the mouse
 	shape                      *draw2d.Path
-	window, offscreen          *glfw.Window
+	window                     draw2dui.CursorSetter
 	gc                         *draw2d.GraphicContext // gc may be overwritten so it's a pointer to an interface
+	clipboard                  draw2dui.Clipboard
+	theme                      *draw2dui.Theme
 	name                       string
 }
 
-// NewTextField creates a new TextField widget
-func NewTextField(gc *draw2d.GraphicContext, window, offscreen *glfw.Window, x, y, width float64, text string, maxlen int) *TextField {
+// NewTextField creates a new TextField widget, holding at most maxlen characters. window may be nil to run
+// headless.
+func NewTextField(gc *draw2d.GraphicContext, window draw2dui.CursorSetter, x, y, width float64, text string, maxlen int) *TextField {
 	textField := &TextField{
-		cursor:    &Cursor{text: text},
-		gc:        gc,
-		window:    window,
-		offscreen: offscreen,
-		x:         x,
-		y:         y,
-		width:     width,
-		height:    (*gc).GetFontSize() + 7,
-		maxlen:    maxlen,
-		enabled:   true,
-		shape:     &draw2d.Path{},
-		redraw:    true,
-		name:      draw2dui.NameWidget("TextField"),
-	}
-	textField.reshape()
+		cursor:  newCursor(text),
+		gc:      gc,
+		window:  window,
+		x:       x,
+		y:       y,
+		width:   width,
+		maxlen:  maxlen,
+		enabled: true,
+		shape:   &draw2d.Path{},
+		redraw:  true,
+		name:    draw2dui.NameWidget("TextField"),
+	}
+	textField.SetTheme(defaultTheme)
 	return textField
 }
 
@@ -329,22 +595,32 @@ func (tf *TextField) Name() string {
 	return tf.name
 }
 
+// NeedsRedraw returns true if tf changed since it was last drawn
+func (tf *TextField) NeedsRedraw() bool {
+	return tf.redraw
+}
+
 // Draw draws the widget, selected determines if the widget displays as selected or not, and forceRedraw
 // forces a full redraw of the widget.
 func (tf *TextField) Draw(selected, forceRedraw bool) {
 	if tf.redraw || forceRedraw {
 		gc := *tf.gc
 		gc.Save()
-		tf.clear(gc, false)
-		gl.LineWidth(1)
-		gc.SetFillColor(color.RGBA{255, 255, 255, 0xff})
-		gc.SetStrokeColor(color.RGBA{0, 0, 0, 0xff})
+		gc.SetLineWidth(tf.theme.BorderWidth)
+		gc.SetFillColor(backgroundColor(tf.theme, tf.dropTarget))
+		gc.SetStrokeColor(borderColor(tf.theme, selected || tf.dropTarget, tf.enabled))
 		gc.FillStroke(tf.shape)
-		gc.SetFillColor(color.RGBA{0, 0, 0, 0xff})
+		x, y := tf.x+tf.theme.TextPadding, tf.y+tf.theme.LineHeight(gc)
+		start, end := tf.cursor.Selection()
+		fillSelection(gc, tf.theme, tf.cursor.text[tf.cursor.iOffset:], start-tf.cursor.iOffset,
+			end-tf.cursor.iOffset, x, y, tf.width-tf.theme.TextPadding*2)
+		fg := textColor(tf.theme, tf.enabled)
+		gc.SetFillColor(fg)
+		gc.SetStrokeColor(fg)
 		if selected {
-			fillStringAtWidthCursor(*tf.gc, tf.cursor, tf.x+1, tf.y+3+gc.GetFontSize(), tf.width-2)
+			fillStringAtWidthCursor(*tf.gc, tf.cursor, x, y, tf.width-tf.theme.TextPadding*2)
 		} else {
-			fillStringAtWidth(*tf.gc, tf.cursor.text[tf.cursor.iOffset:], tf.x+1, tf.y+3+gc.GetFontSize(), tf.width-2)
+			fillStringAtWidth(*tf.gc, tf.cursor.text[tf.cursor.iOffset:], x, y, tf.width-tf.theme.TextPadding*2)
 		}
 		gc.Restore()
 
@@ -352,20 +628,34 @@ func (tf *TextField) Draw(selected, forceRedraw bool) {
 	}
 }
 
-// clear clears tf's border to make redrawing look more consistent without a complete screen clear. If fill
-// is true, it also fills the shape with white.
-func (tf *TextField) clear(gc draw2d.GraphicContext, fill bool) {
-	gl.LineWidth(3)
-	if !fill {
-		gc.SetStrokeColor(color.RGBA{255, 255, 255, 0xff})
-		gc.Stroke(tf.shape)
-	} else {
-		gc.Save()
-		gc.SetStrokeColor(color.RGBA{255, 255, 255, 0xff})
-		gc.SetFillColor(color.RGBA{255, 255, 255, 0xff})
-		gc.FillStroke(tf.shape)
-		gc.Restore()
+// SetTheme restyles tf with t
+func (tf *TextField) SetTheme(t *draw2dui.Theme) {
+	tf.theme = t
+	tf.height = t.LineHeight(*tf.gc) + (t.TextPadding+t.BorderWidth)*2
+	tf.reshape()
+}
+
+// SetClock sets the Clock tf's cursor blinks with
+func (tf *TextField) SetClock(c draw2dui.Clock) {
+	tf.cursor.clock = c
+}
+
+// SetHistoryLength sets how many edits to tf can be undone, 0 turns undo off. It's 100 by default.
+func (tf *TextField) SetHistoryLength(length int) {
+	tf.cursor.SetHistoryLength(length)
+}
+
+// SetClipboard sets the Clipboard tf copies to and pastes from
+func (tf *TextField) SetClipboard(c draw2dui.Clipboard) {
+	tf.clipboard = c
+}
+
+// NextDeadline returns when tf's cursor next blinks while it's selected
+func (tf *TextField) NextDeadline(selected bool) (time.Time, bool) {
+	if !selected {
+		return time.Time{}, false
 	}
+	return tf.cursor.NextBlink(), true
 }
 
 // Handle processes tf's cursor
@@ -379,61 +669,132 @@ func (tf *TextField) Handle(selected bool) bool {
 	return false
 }
 
-// KeyPress has the widget process a KeyPress event
-func (tf *TextField) KeyPress(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) draw2dui.Event {
-	if action == glfw.Release {
+// KeyPress has the widget process a KeyPress event. Shift+arrow keys select text and Ctrl+A selects all of
+// it. Ctrl+C and Ctrl+X copy and cut the selection, doing nothing if nothing is selected, and Ctrl+V pastes
+// over the selection. Ctrl+Z undoes edits, and Ctrl+Shift+Z or Ctrl+Y redo them.
+func (tf *TextField) KeyPress(key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if action == draw2dui.Release {
 		return draw2dui.EventNone
 	}
+	if handled, changed := tf.cursor.historyKey(key, mods); handled {
+		if !changed {
+			return draw2dui.EventNone
+		}
+		tf.redraw = true
+		return draw2dui.EventAction
+	}
+	if handled, changed := tf.cursor.clipboardKey(tf.clipboard, key, mods, tf.maxlen, true); handled {
+		if !changed {
+			return draw2dui.EventNone
+		}
+		tf.redraw = true
+		return draw2dui.EventAction
+	}
 	switch key {
 	default:
 		return draw2dui.EventNone
-	case glfw.KeyLeft:
-		if tf.cursor.MoveLeft() {
+	case draw2dui.KeyLeft:
+		if tf.cursor.Left(mods&draw2dui.ModShift != 0) {
 			tf.redraw = true
 			return draw2dui.EventAction
 		}
-	case glfw.KeyRight:
-		if tf.cursor.MoveRight() {
+	case draw2dui.KeyRight:
+		if tf.cursor.Right(mods&draw2dui.ModShift != 0) {
 			tf.redraw = true
 			return draw2dui.EventAction
 		}
-	case glfw.KeyBackspace:
-		if tf.cursor.Backspace() {
+	case draw2dui.KeyBackspace:
+		if tf.cursor.Edit(func() bool { return tf.cursor.DeleteSelection() || tf.cursor.Backspace() }) {
 			tf.redraw = true
 			return draw2dui.EventAction
 		}
-	case glfw.KeyEnter:
+	case draw2dui.KeyDelete:
+		if tf.cursor.Edit(func() bool { return tf.cursor.DeleteSelection() || tf.cursor.Delete() }) {
+			tf.redraw = true
+			return draw2dui.EventAction
+		}
+	case draw2dui.KeyA:
+		if mods&draw2dui.ModControl != 0 && tf.cursor.SelectAll() {
+			tf.redraw = true
+		}
+	case draw2dui.KeyEnter:
 		return draw2dui.EventConfirm
 	}
 	return draw2dui.EventNone
 }
 
-// CharPress adds a character to the textfield
+// DragOver highlights tf while files are dragged over it
+func (tf *TextField) DragOver(xpos, ypos float64, paths []string) bool {
+	if len(paths) == 0 {
+		return false
+	}
+	if !tf.dropTarget {
+		tf.dropTarget = true
+		tf.redraw = true
+	}
+	return true
+}
+
+// DragLeave removes tf's highlight
+func (tf *TextField) DragLeave() {
+	if tf.dropTarget {
+		tf.dropTarget = false
+		tf.redraw = true
+	}
+}
+
+// Drop replaces tf's text with the first of paths, moving the text cursor to its end
+func (tf *TextField) Drop(xpos, ypos float64, paths []string) draw2dui.Event {
+	tf.DragLeave()
+	if len(paths) == 0 {
+		return draw2dui.EventNone
+	}
+	tf.SetString(paths[0])
+	tf.SetInt(len(tf.GetString()))
+	return draw2dui.EventAction
+}
+
+// CharPress adds a character to the textfield, replacing the selected text
 func (tf *TextField) CharPress(char rune) draw2dui.Event {
-	if !utf8.ValidRune(char) || len(tf.GetString()) >= tf.maxlen {
+	text := tf.GetString()
+	start, end := tf.cursor.Selection()
+	if !typeable(char) || charCount(text[:start]+string(char)+text[end:]) > tf.maxlen {
 		return draw2dui.EventNone
 	}
-	tf.cursor.Insert(string(char))
+	tf.cursor.Type(string(char))
 	tf.redraw = true
 	return draw2dui.EventAction
 }
 
-// MMove has the widget process a MouseMove event
+// MMove has the widget process a MouseMove event, selecting text while the mouse is dragged
 func (tf *TextField) MMove(xpos, ypos float64) draw2dui.Event {
+	if tf.dragging && !math.IsInf(xpos, 0) {
+		i := tf.cursor.i
+		tf.cursor.MoveToX(*tf.gc, tf.x+tf.theme.TextPadding, xpos, tf.width-tf.theme.TextPadding*2)
+		if tf.cursor.i != i {
+			tf.redraw = true
+		}
+		return draw2dui.EventHasCursor
+	}
 	if !tf.IsInside(xpos, ypos) {
 		tf.hasCursor = false
 		return draw2dui.EventNone
 	}
 	if !tf.hasCursor {
-		tf.window.SetCursor(glfw.CreateStandardCursor(int(glfw.IBeamCursor)))
+		setCursor(tf.window, draw2dui.IBeamCursor)
 		tf.hasCursor = true
 	}
 	return draw2dui.EventHasCursor
 }
 
-// MClick has the widget process a MouseClick event
-func (tf *TextField) MClick(xpos, ypos float64, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) draw2dui.Event {
-	if button == glfw.MouseButtonLeft && action == glfw.Press {
+// MClick has the widget process a MouseClick event. Pressing inside of tf moves the text cursor there, and
+// dragging selects text until the button is released. Shift extends the selection to where tf is clicked.
+func (tf *TextField) MClick(xpos, ypos float64, button draw2dui.MouseButton, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if button == draw2dui.MouseButtonLeft && action == draw2dui.Release {
+		tf.dragging = false
+		return draw2dui.EventNone
+	}
+	if button == draw2dui.MouseButtonLeft && action == draw2dui.Press {
 		tf.redraw = true
 		if !tf.IsInside(xpos, ypos) {
 			return draw2dui.EventNone
@@ -442,13 +803,16 @@ func (tf *TextField) MClick(xpos, ypos float64, button glfw.MouseButton, action
 		return draw2dui.EventNone
 	}
 	tf.cursor.drawCursor = false // gets swapped to true before next draw
-	tf.cursor.MoveToX(*tf.gc, tf.x, xpos, tf.width)
+	tf.cursor.MoveToX(*tf.gc, tf.x+tf.theme.TextPadding, xpos, tf.width-tf.theme.TextPadding*2)
+	if mods&draw2dui.ModShift == 0 {
+		tf.cursor.Deselect()
+	}
+	tf.dragging = true
 	return draw2dui.EventSelected
 }
 
 // SetPos changes the widget's x, y coordinates
 func (tf *TextField) SetPos(x, y float64) {
-	tf.clear(*tf.gc, true)
 	tf.x, tf.y = x, y
 	tf.reshape()
 }
@@ -460,7 +824,6 @@ func (tf *TextField) GetPos() (float64, float64) {
 
 // SetDimensions sets tf's drawn width and height
 func (tf *TextField) SetDimensions(w, h float64) {
-	tf.clear(*tf.gc, true)
 	tf.width, tf.height = w, h
 	tf.reshape()
 }
@@ -470,22 +833,21 @@ func (tf *TextField) GetDimensions() (float64, float64) {
 	return tf.width, tf.height
 }
 
-// IsInside checks if point x, y is inside of the widget's boundaries. It uses tf.offscreen as a pallet
+// IsInside checks if point x, y is inside of the widget's boundaries
 func (tf *TextField) IsInside(x, y float64) bool {
-	return draw2dglkit.IsPointInShape(*tf.gc, tf.offscreen, x, y, tf.shape)
+	return draw2dui.IsPointInPath(tf.shape, x, y)
 }
 
-// SetString sets tf's text, using tf.maxlen as the max length
+// SetString sets tf's text, cut short to tf.maxlen characters. It can be undone like an edit.
 func (tf *TextField) SetString(s string) {
-	if len(s) > tf.maxlen {
-		tf.cursor.text = s[:tf.maxlen]
-	} else {
+	s = truncate(s, tf.maxlen)
+	tf.cursor.Edit(func() bool {
+		changed := tf.cursor.text != s
 		tf.cursor.text = s
-	}
-	if tf.GetInt() > len(tf.GetString()) {
-		// BUG(x) shortening strings can end up with bad iOffset / iEdge
-		tf.SetInt(len(tf.GetString()))
-	}
+		return changed
+	})
+	tf.cursor.clamp()
+	tf.cursor.Deselect()
 	tf.redraw = true
 }
 
@@ -494,27 +856,21 @@ func (tf *TextField) GetString() string {
 	return tf.cursor.text
 }
 
-// SetInt moves tf's text cursor
+// SetInt moves tf's text cursor to byte offset i in its text, clearing the selection. An offset inside of a
+// character moves it to the start of the character.
 func (tf *TextField) SetInt(i int) {
-	if tf.cursor.MoveTo(i) {
+	if tf.cursor.MoveToOffset(i) || tf.cursor.anchor != tf.cursor.i {
 		tf.redraw = true
 	}
+	tf.cursor.Deselect()
 }
 
-// GetInt returns tf's text cursor's position
+// GetInt returns tf's text cursor's position as a byte offset in its text, so GetString()[:GetInt()] is the
+// text before it
 func (tf *TextField) GetInt() int {
 	return tf.cursor.i
 }
 
-// SetData does nothing
-func (tf *TextField) SetData(d interface{}) {
-}
-
-// GetData returns nil
-func (tf *TextField) GetData() interface{} {
-	return nil
-}
-
 // SetEnabled enables or disables the widget
 func (tf *TextField) SetEnabled(enabled bool) {
 	if tf.enabled != enabled {
@@ -529,37 +885,48 @@ func (tf *TextField) GetEnabled() bool {
 }
 
 type Label struct {
+	draw2dui.Handlers
 	x, y, width, height float64
 	redraw              bool
 	shape               *draw2d.Path
-	window, offscreen   *glfw.Window
+	window              draw2dui.CursorSetter
 	gc                  *draw2d.GraphicContext // gc may be overwritten so it's a pointer to an interface
+	theme               *draw2dui.Theme
 	name, text          string
 }
 
-func NewLabel(gc *draw2d.GraphicContext, window, offscreen *glfw.Window, x, y float64, text string) *Label {
+// NewLabel creates a new Label widget. window may be nil to run headless.
+func NewLabel(gc *draw2d.GraphicContext, window draw2dui.CursorSetter, x, y float64, text string) *Label {
 	Label := &Label{
-		gc:        gc,
-		window:    window,
-		offscreen: offscreen,
-		x:         x,
-		y:         y,
-		height:    (*gc).GetFontSize() + 6,
-		shape:     &draw2d.Path{},
-		redraw:    true,
-		name:      draw2dui.NameWidget("Label"),
-		text:      text,
-	}
-	Label.reshape()
+		gc:     gc,
+		window: window,
+		x:      x,
+		y:      y,
+		shape:  &draw2d.Path{},
+		redraw: true,
+		name:   draw2dui.NameWidget("Label"),
+		text:   text,
+	}
+	Label.SetTheme(defaultTheme)
 	return Label
 }
 
+// NaturalSize returns the width and height lbl needs to fit its text
+func (lbl *Label) NaturalSize() (width, height float64) {
+	x, _, width, _ := (*lbl.gc).GetStringBounds(lbl.text)
+	padding := (lbl.theme.TextPadding + lbl.theme.BorderWidth) * 2
+	return width + x + padding, lbl.theme.LineHeight(*lbl.gc) + padding
+}
+
+// resize fits lbl to its text, then reshapes it
+func (lbl *Label) resize() {
+	lbl.width, lbl.height = lbl.NaturalSize()
+	lbl.reshape()
+}
+
 // reshape recreates lbl's path, which is used for drawing it to the screen
 func (lbl *Label) reshape() {
 	lbl.shape = &draw2d.Path{}
-	var x float64
-	x, _, lbl.width, _ = (*lbl.gc).GetStringBounds(lbl.text)
-	lbl.width += x + 5
 	draw2dkit.Rectangle(lbl.shape, lbl.x, lbl.y, lbl.x+lbl.width-1, lbl.y+lbl.height-1)
 	lbl.redraw = true
 }
@@ -569,6 +936,11 @@ func (lbl *Label) Name() string {
 	return lbl.name
 }
 
+// NeedsRedraw returns true if lbl changed since it was last drawn
+func (lbl *Label) NeedsRedraw() bool {
+	return lbl.redraw
+}
+
 // Draw draws the widget, selected determines if the widget displays as selected or not, and forceRedraw
 // forces a full redraw of the widget.
 func (lbl *Label) Draw(selected, forceRedraw bool) {
@@ -576,26 +948,20 @@ func (lbl *Label) Draw(selected, forceRedraw bool) {
 		gc := *lbl.gc
 		gc.Save()
 		gc.BeginPath()
-		gl.LineWidth(1)
-		fg := color.RGBA{0, 0, 0, 0xff}
-		bg := color.RGBA{255, 255, 255, 0xff}
-		gc.SetFillColor(bg)
+		gc.SetFillColor(lbl.theme.Background)
 		gc.Fill(lbl.shape)
-		gc.SetFillColor(fg)
-		gc.FillStringAt(lbl.text, lbl.x+1, lbl.y+1+gc.GetFontSize())
+		gc.SetFillColor(lbl.theme.Foreground)
+		gc.FillStringAt(lbl.text, lbl.x+lbl.theme.TextPadding, lbl.y+lbl.theme.TextPadding+gc.GetFontSize())
 		gc.Restore()
 
 		lbl.redraw = false
 	}
 }
 
-// clear fills the shape with white
-func (lbl *Label) clear(gc draw2d.GraphicContext) {
-	gc.Save()
-	gc.BeginPath()
-	gc.SetFillColor(color.RGBA{255, 255, 255, 0xff})
-	gc.Fill(lbl.shape)
-	gc.Restore()
+// SetTheme restyles lbl with t
+func (lbl *Label) SetTheme(t *draw2dui.Theme) {
+	lbl.theme = t
+	lbl.resize()
 }
 
 // Handle returns false
@@ -604,7 +970,7 @@ func (lbl *Label) Handle(selected bool) bool {
 }
 
 // KeyPress returns draw2dui.EventNone
-func (lbl *Label) KeyPress(key glfw.Key, action glfw.Action, mods glfw.ModifierKey) draw2dui.Event {
+func (lbl *Label) KeyPress(key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
 	return draw2dui.EventNone
 }
 
@@ -619,13 +985,12 @@ func (lbl *Label) MMove(xpos, ypos float64) draw2dui.Event {
 }
 
 // MClick returns draw2dui.EventNone
-func (lbl *Label) MClick(xpos, ypos float64, button glfw.MouseButton, action glfw.Action, mods glfw.ModifierKey) draw2dui.Event {
+func (lbl *Label) MClick(xpos, ypos float64, button draw2dui.MouseButton, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
 	return draw2dui.EventNone
 }
 
 // SetPos changes the widget's x, y coordinates
 func (lbl *Label) SetPos(x, y float64) {
-	lbl.clear(*lbl.gc)
 	lbl.x, lbl.y = x, y
 	lbl.reshape()
 }
@@ -635,11 +1000,11 @@ func (lbl *Label) GetPos() (float64, float64) {
 	return lbl.x, lbl.y
 }
 
-// SetDimensions sets lbl's drawn width and height
+// SetDimensions sets lbl's drawn width and height. Changing its text or restyling it fits its width to its
+// text again.
 func (lbl *Label) SetDimensions(w, h float64) {
-	lbl.clear(*lbl.gc)
 	lbl.width, lbl.height = w, h
-	lbl.reshape() // reshape overwrites width
+	lbl.reshape()
 }
 
 // GetDimensions returns lbl's drawn width and height
@@ -647,16 +1012,15 @@ func (lbl *Label) GetDimensions() (float64, float64) {
 	return lbl.width, lbl.height
 }
 
-// IsInside checks if point x, y is inside of the widget's boundaries. It uses lbl.offscreen as a pallet
+// IsInside checks if point x, y is inside of the widget's boundaries
 func (lbl *Label) IsInside(x, y float64) bool {
-	return draw2dglkit.IsPointInShape(*lbl.gc, lbl.offscreen, x, y, lbl.shape)
+	return draw2dui.IsPointInPath(lbl.shape, x, y)
 }
 
 // SetString sets lbl's text
 func (lbl *Label) SetString(s string) {
-	lbl.clear(*lbl.gc)
 	lbl.text = s
-	lbl.reshape()
+	lbl.resize()
 }
 
 // GetString returns lbl's text
@@ -664,29 +1028,7 @@ func (lbl *Label) GetString() string {
 	return lbl.text
 }
 
-// SetInt does nothing
-func (lbl *Label) SetInt(i int) {
-}
-
-// GetInt returns -1
-func (lbl *Label) GetInt() int {
-	return -1
-}
-
-// SetData does nothing
-func (lbl *Label) SetData(d interface{}) {
-}
-
-// GetData returns nil
-func (lbl *Label) GetData() interface{} {
-	return nil
-}
-
-// SetEnabled does nothing
-func (lbl *Label) SetEnabled(enabled bool) {
-}
-
-// GetEnabled returns true
-func (lbl *Label) GetEnabled() bool {
-	return true
+// Focusable returns false, Labels can't be selected with the keyboard
+func (lbl *Label) Focusable() bool {
+	return false
 }
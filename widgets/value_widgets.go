@@ -0,0 +1,782 @@
+// Copyright (c) 2016, redstarcoder
+package widgets
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/llgcode/draw2d"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"github.com/redstarcoder/draw2dui"
+)
+
+// Checkbox is a widget holding a bool, toggled by clicking it or pressing Space. It implements
+// draw2dui.ValueWidget[bool].
+type Checkbox struct {
+	draw2dui.Handlers
+	draw2dui.Observable[bool]
+	x, y, width, height        float64
+	enabled, redraw, hasCursor bool
+	shape                      *draw2d.Path
+	window                     draw2dui.CursorSetter
+	gc                         *draw2d.GraphicContext // gc may be overwritten so it's a pointer to an interface
+	theme                      *draw2dui.Theme
+	name, text                 string
+}
+
+// NewCheckbox creates a new Checkbox widget labelled with text. window may be nil to run headless.
+func NewCheckbox(gc *draw2d.GraphicContext, window draw2dui.CursorSetter, x, y float64, text string, checked bool) *Checkbox {
+	cb := &Checkbox{
+		Observable: draw2dui.NewObservable(checked),
+		gc:         gc,
+		window:     window,
+		x:          x,
+		y:          y,
+		enabled:    true,
+		redraw:     true,
+		name:       draw2dui.NameWidget("Checkbox"),
+		text:       text,
+	}
+	cb.OnChange(func(bool) { cb.redraw = true })
+	cb.SetTheme(defaultTheme)
+	return cb
+}
+
+// NaturalSize returns the width and height cb needs to fit its box and label
+func (cb *Checkbox) NaturalSize() (width, height float64) {
+	_, _, width, _ = (*cb.gc).GetStringBounds(cb.text)
+	return width + cb.boxSize() + cb.theme.ButtonPadding + cb.theme.TextPadding*2,
+		cb.theme.LineHeight(*cb.gc) + (cb.theme.TextPadding+cb.theme.BorderWidth)*2
+}
+
+// resize fits cb to its box and label, then reshapes it
+func (cb *Checkbox) resize() {
+	cb.width, cb.height = cb.NaturalSize()
+	cb.reshape()
+}
+
+// reshape recreates cb's path, which is used for drawing it to the screen
+func (cb *Checkbox) reshape() {
+	cb.shape = &draw2d.Path{}
+	draw2dkit.Rectangle(cb.shape, cb.x, cb.y, cb.x+cb.width-1, cb.y+cb.height-1)
+	cb.redraw = true
+}
+
+// boxSize returns the width and height of the box cb draws its check mark in
+func (cb *Checkbox) boxSize() float64 {
+	return (*cb.gc).GetFontSize()
+}
+
+// Name returns cb's name
+func (cb *Checkbox) Name() string {
+	return cb.name
+}
+
+// NeedsRedraw returns true if cb changed since it was last drawn
+func (cb *Checkbox) NeedsRedraw() bool {
+	return cb.redraw
+}
+
+// Draw draws the widget, selected determines if the widget displays as selected or not, and forceRedraw
+// forces a full redraw of the widget.
+func (cb *Checkbox) Draw(selected, forceRedraw bool) {
+	if cb.redraw || forceRedraw {
+		gc := *cb.gc
+		gc.Save()
+		gc.BeginPath()
+		gc.SetFillColor(cb.theme.Background)
+		gc.Fill(cb.shape)
+
+		size := cb.boxSize()
+		x, y := cb.x+cb.theme.TextPadding, cb.y+(cb.height-size)/2
+		gc.SetLineWidth(cb.theme.BorderWidth)
+		gc.SetStrokeColor(borderColor(cb.theme, selected, cb.enabled))
+		gc.BeginPath()
+		draw2dkit.Rectangle(gc, x, y, x+size, y+size)
+		gc.Stroke()
+		fg := textColor(cb.theme, cb.enabled)
+		if cb.Get() {
+			gc.SetStrokeColor(fg)
+			gc.SetLineWidth(2)
+			gc.BeginPath()
+			gc.MoveTo(x+size*0.2, y+size*0.5)
+			gc.LineTo(x+size*0.4, y+size*0.75)
+			gc.LineTo(x+size*0.8, y+size*0.25)
+			gc.Stroke()
+		}
+		gc.SetFillColor(fg)
+		gc.FillStringAt(cb.text, x+size+cb.theme.ButtonPadding, cb.y+cb.theme.LineHeight(gc))
+		gc.Restore()
+
+		cb.redraw = false
+	}
+}
+
+// SetTheme restyles cb with t
+func (cb *Checkbox) SetTheme(t *draw2dui.Theme) {
+	cb.theme = t
+	cb.resize()
+}
+
+// Handle returns false
+func (cb *Checkbox) Handle(selected bool) bool {
+	return false
+}
+
+// KeyPress toggles cb when Space is pressed
+func (cb *Checkbox) KeyPress(key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if action != draw2dui.Press || key != draw2dui.KeySpace || !cb.enabled {
+		return draw2dui.EventNone
+	}
+	cb.Set(!cb.Get())
+	return draw2dui.EventAction
+}
+
+// CharPress returns draw2dui.EventNone
+func (cb *Checkbox) CharPress(char rune) draw2dui.Event {
+	return draw2dui.EventNone
+}
+
+// MMove has the widget process a MouseMove event
+func (cb *Checkbox) MMove(xpos, ypos float64) draw2dui.Event {
+	inside := cb.IsInside(xpos, ypos)
+	if cb.hasCursor && !inside {
+		cb.hasCursor = false
+		return draw2dui.EventAction
+	} else if !inside {
+		return draw2dui.EventNone
+	}
+	if !cb.hasCursor {
+		setCursor(cb.window, draw2dui.HandCursor)
+		cb.hasCursor = true
+	}
+	return draw2dui.EventHasCursor
+}
+
+// MClick toggles cb when it's clicked
+func (cb *Checkbox) MClick(xpos, ypos float64, button draw2dui.MouseButton, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if button != draw2dui.MouseButtonLeft || action != draw2dui.Press || !cb.enabled || !cb.IsInside(xpos, ypos) {
+		return draw2dui.EventNone
+	}
+	cb.Set(!cb.Get())
+	return draw2dui.EventAction
+}
+
+// SetPos changes the widget's x, y coordinates
+func (cb *Checkbox) SetPos(x, y float64) {
+	cb.x, cb.y = x, y
+	cb.reshape()
+}
+
+// GetPos retrieves the widget's x, y coordinates
+func (cb *Checkbox) GetPos() (float64, float64) {
+	return cb.x, cb.y
+}
+
+// SetDimensions sets cb's drawn width and height. Changing its label or restyling it fits its width to its
+// box and label again.
+func (cb *Checkbox) SetDimensions(w, h float64) {
+	cb.width, cb.height = w, h
+	cb.reshape()
+}
+
+// GetDimensions returns cb's drawn width and height
+func (cb *Checkbox) GetDimensions() (float64, float64) {
+	return cb.width, cb.height
+}
+
+// IsInside checks if point x, y is inside of the widget's boundaries
+func (cb *Checkbox) IsInside(x, y float64) bool {
+	return draw2dui.IsPointInPath(cb.shape, x, y)
+}
+
+// SetString sets cb's label
+func (cb *Checkbox) SetString(s string) {
+	cb.text = s
+	cb.resize()
+}
+
+// GetString returns cb's label
+func (cb *Checkbox) GetString() string {
+	return cb.text
+}
+
+// SetEnabled enables or disables the widget
+func (cb *Checkbox) SetEnabled(enabled bool) {
+	if cb.enabled != enabled {
+		cb.enabled = enabled
+		cb.redraw = true
+	}
+}
+
+// GetEnabled returns whether the widget is enabled or not
+func (cb *Checkbox) GetEnabled() bool {
+	return cb.enabled
+}
+
+// Slider is a widget holding a float64 between a minimum and a maximum, changed by dragging its knob or
+// with the arrow keys. It implements draw2dui.ValueWidget[float64].
+type Slider struct {
+	draw2dui.Handlers
+	draw2dui.Observable[float64]
+	x, y, width, height                  float64
+	min, max, step                       float64
+	enabled, redraw, hasCursor, dragging bool
+	shape                                *draw2d.Path
+	window                               draw2dui.CursorSetter
+	gc                                   *draw2d.GraphicContext // gc may be overwritten so it's a pointer to an interface
+	theme                                *draw2dui.Theme
+	name                                 string
+}
+
+// NewSlider creates a new Slider widget holding value, between min and max. The arrow keys move it by a
+// hundredth of the range. window may be nil to run headless. It panics if min is greater than max.
+func NewSlider(gc *draw2d.GraphicContext, window draw2dui.CursorSetter, x, y, width, min, max, value float64) *Slider {
+	if !(min <= max) {
+		panic(fmt.Sprintf("draw2dui: slider range %v to %v is invalid", min, max))
+	}
+	s := &Slider{
+		gc:      gc,
+		window:  window,
+		x:       x,
+		y:       y,
+		width:   width,
+		min:     min,
+		max:     max,
+		step:    (max - min) / 100,
+		enabled: true,
+		redraw:  true,
+		name:    draw2dui.NameWidget("Slider"),
+	}
+	s.Set(value)
+	s.OnChange(func(float64) { s.redraw = true })
+	s.SetTheme(defaultTheme)
+	return s
+}
+
+// reshape recreates s's path, which is used for drawing it to the screen
+func (s *Slider) reshape() {
+	s.shape = &draw2d.Path{}
+	draw2dkit.Rectangle(s.shape, s.x, s.y, s.x+s.width-1, s.y+s.height-1)
+	s.redraw = true
+}
+
+// SetStep sets how far the arrow keys move s. Values set afterwards are rounded to a multiple of step
+// above the minimum, unless step is 0.
+func (s *Slider) SetStep(step float64) {
+	s.step = step
+	s.Set(s.Get())
+}
+
+// Set sets s's value, clamped to its range and rounded to its step. NaN is ignored.
+func (s *Slider) Set(v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+	if s.step > 0 {
+		v = s.min + math.Floor((v-s.min)/s.step+0.5)*s.step
+	}
+	s.Observable.Set(math.Max(s.min, math.Min(s.max, v)))
+}
+
+// knobRadius returns the radius of s's knob
+func (s *Slider) knobRadius() float64 {
+	return s.height/2 - s.theme.BorderWidth*2
+}
+
+// track returns where s's track starts and its length
+func (s *Slider) track() (start, length float64) {
+	r := s.knobRadius() + s.theme.BorderWidth
+	return s.x + r, s.width - r*2
+}
+
+// valueAt returns the value s's knob would have at xpos
+func (s *Slider) valueAt(xpos float64) float64 {
+	start, length := s.track()
+	if length <= 0 {
+		return s.min
+	}
+	return s.min + (xpos-start)/length*(s.max-s.min)
+}
+
+// Name returns s's name
+func (s *Slider) Name() string {
+	return s.name
+}
+
+// NeedsRedraw returns true if s changed since it was last drawn
+func (s *Slider) NeedsRedraw() bool {
+	return s.redraw
+}
+
+// Draw draws the widget, selected determines if the widget displays as selected or not, and forceRedraw
+// forces a full redraw of the widget.
+func (s *Slider) Draw(selected, forceRedraw bool) {
+	if s.redraw || forceRedraw {
+		gc := *s.gc
+		gc.Save()
+		gc.BeginPath()
+		gc.SetFillColor(s.theme.Background)
+		gc.Fill(s.shape)
+
+		start, length := s.track()
+		cy := s.y + s.height/2
+		gc.SetLineWidth(s.theme.BorderWidth * 2)
+		gc.SetStrokeColor(borderColor(s.theme, selected, s.enabled))
+		gc.BeginPath()
+		gc.MoveTo(start, cy)
+		gc.LineTo(start+length, cy)
+		gc.Stroke()
+
+		pos := 0.
+		if s.max > s.min {
+			pos = (s.Get() - s.min) / (s.max - s.min)
+		}
+		fg, bg := textColor(s.theme, s.enabled), s.theme.Background
+		if (s.hasCursor || s.dragging) && s.enabled {
+			fg, bg = s.theme.HoverForeground, s.theme.HoverBackground
+		}
+		gc.SetLineWidth(s.theme.BorderWidth)
+		gc.SetFillColor(bg)
+		gc.SetStrokeColor(fg)
+		gc.BeginPath()
+		draw2dkit.Circle(gc, start+pos*length, cy, s.knobRadius())
+		gc.FillStroke()
+		gc.Restore()
+
+		s.redraw = false
+	}
+}
+
+// SetTheme restyles s with t
+func (s *Slider) SetTheme(t *draw2dui.Theme) {
+	s.theme = t
+	s.height = t.LineHeight(*s.gc) + (t.TextPadding+t.BorderWidth)*2
+	s.reshape()
+}
+
+// Handle stops dragging s's knob once s isn't selected, in case the button was released somewhere s didn't
+// hear about it
+func (s *Slider) Handle(selected bool) bool {
+	if !selected && s.dragging {
+		s.dragging = false
+		s.redraw = true
+		return true
+	}
+	return false
+}
+
+// KeyPress moves s by its step with the arrow keys, and to its minimum or maximum with Home or End
+func (s *Slider) KeyPress(key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if action == draw2dui.Release || !s.enabled {
+		return draw2dui.EventNone
+	}
+	old := s.Get()
+	switch key {
+	case draw2dui.KeyLeft, draw2dui.KeyDown:
+		s.Set(old - s.step)
+	case draw2dui.KeyRight, draw2dui.KeyUp:
+		s.Set(old + s.step)
+	case draw2dui.KeyHome:
+		s.Set(s.min)
+	case draw2dui.KeyEnd:
+		s.Set(s.max)
+	}
+	if s.Get() == old {
+		return draw2dui.EventNone
+	}
+	return draw2dui.EventAction
+}
+
+// CharPress returns draw2dui.EventNone
+func (s *Slider) CharPress(char rune) draw2dui.Event {
+	return draw2dui.EventNone
+}
+
+// MMove has the widget process a MouseMove event, moving the knob while it's being dragged
+func (s *Slider) MMove(xpos, ypos float64) draw2dui.Event {
+	if s.dragging && !math.IsInf(xpos, 0) {
+		s.Set(s.valueAt(xpos))
+		return draw2dui.EventHasCursor
+	}
+	inside := s.IsInside(xpos, ypos)
+	if s.hasCursor && !inside {
+		s.hasCursor = false
+		s.redraw = true
+		return draw2dui.EventAction
+	} else if !inside {
+		return draw2dui.EventNone
+	}
+	if !s.hasCursor {
+		setCursor(s.window, draw2dui.HResizeCursor)
+		s.hasCursor = true
+		s.redraw = true
+	}
+	return draw2dui.EventHasCursor
+}
+
+// MClick has the widget process a MouseClick event. Pressing inside of s moves the knob there and starts
+// dragging it, until the button is released.
+func (s *Slider) MClick(xpos, ypos float64, button draw2dui.MouseButton, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if button != draw2dui.MouseButtonLeft {
+		return draw2dui.EventNone
+	}
+	if action == draw2dui.Release {
+		if s.dragging {
+			s.dragging = false
+			s.redraw = true
+		}
+		return draw2dui.EventNone
+	}
+	if action != draw2dui.Press || !s.enabled || !s.IsInside(xpos, ypos) {
+		return draw2dui.EventNone
+	}
+	s.dragging = true
+	s.Set(s.valueAt(xpos))
+	return draw2dui.EventSelected
+}
+
+// SetPos changes the widget's x, y coordinates
+func (s *Slider) SetPos(x, y float64) {
+	s.x, s.y = x, y
+	s.reshape()
+}
+
+// GetPos retrieves the widget's x, y coordinates
+func (s *Slider) GetPos() (float64, float64) {
+	return s.x, s.y
+}
+
+// SetDimensions sets s's drawn width and height
+func (s *Slider) SetDimensions(w, h float64) {
+	s.width, s.height = w, h
+	s.reshape()
+}
+
+// GetDimensions returns s's drawn width and height
+func (s *Slider) GetDimensions() (float64, float64) {
+	return s.width, s.height
+}
+
+// IsInside checks if point x, y is inside of the widget's boundaries
+func (s *Slider) IsInside(x, y float64) bool {
+	return draw2dui.IsPointInPath(s.shape, x, y)
+}
+
+// SetEnabled enables or disables the widget
+func (s *Slider) SetEnabled(enabled bool) {
+	if s.enabled != enabled {
+		s.enabled = enabled
+		s.dragging = false
+		s.redraw = true
+	}
+}
+
+// GetEnabled returns whether the widget is enabled or not
+func (s *Slider) GetEnabled() bool {
+	return s.enabled
+}
+
+// Dropdown is a widget holding one of a list of options of type T. Clicking it, or pressing Space or Enter,
+// opens the list below it; the arrow keys change the selection directly. It implements
+// draw2dui.ValueWidget[T]. An open Dropdown grows to cover what's below it, so it should be registered
+// above those widgets.
+type Dropdown[T comparable] struct {
+	draw2dui.Handlers
+	draw2dui.Observable[T]
+	x, y, width, height              float64 // height is the height of the closed Dropdown
+	minWidth                         float64 // minWidth is the width d was created with
+	enabled, redraw, hasCursor, open bool
+	hover                            int // hover is the option under the mouse while open, -1 for none
+	options                          []T
+	format                           func(T) string
+	shape                            *draw2d.Path
+	window                           draw2dui.CursorSetter
+	gc                               *draw2d.GraphicContext // gc may be overwritten so it's a pointer to an interface
+	theme                            *draw2dui.Theme
+	name                             string
+}
+
+// NewDropdown creates a new Dropdown widget offering options, with selected chosen. Options are displayed
+// with fmt.Sprint unless SetFormat is used. window may be nil to run headless.
+func NewDropdown[T comparable](gc *draw2d.GraphicContext, window draw2dui.CursorSetter, x, y, width float64, options []T, selected T) *Dropdown[T] {
+	d := &Dropdown[T]{
+		Observable: draw2dui.NewObservable(selected),
+		gc:         gc,
+		window:     window,
+		x:          x,
+		y:          y,
+		width:      width,
+		minWidth:   width,
+		enabled:    true,
+		redraw:     true,
+		hover:      -1,
+		options:    options,
+		format:     func(v T) string { return fmt.Sprint(v) },
+		name:       draw2dui.NameWidget("Dropdown"),
+	}
+	d.OnChange(func(T) { d.redraw = true })
+	d.SetTheme(defaultTheme)
+	return d
+}
+
+// reshape recreates d's path, which is used for drawing it to the screen
+func (d *Dropdown[T]) reshape() {
+	d.shape = &draw2d.Path{}
+	_, h := d.GetDimensions()
+	draw2dkit.Rectangle(d.shape, d.x, d.y, d.x+d.width-1, d.y+h-1)
+	d.redraw = true
+}
+
+// SetFormat sets the function used to display options
+func (d *Dropdown[T]) SetFormat(format func(T) string) {
+	d.format = format
+	d.redraw = true
+}
+
+// Options returns the options d offers
+func (d *Dropdown[T]) Options() []T {
+	return d.options
+}
+
+// SetOptions sets the options d offers. The selection is kept even if it isn't one of them.
+func (d *Dropdown[T]) SetOptions(options []T) {
+	d.options = options
+	d.hover = -1
+	d.reshape()
+}
+
+// Index returns the position of the selected option, or -1 if it isn't one of d's options
+func (d *Dropdown[T]) Index() int {
+	for i, o := range d.options {
+		if o == d.Get() {
+			return i
+		}
+	}
+	return -1
+}
+
+// setOpen opens or closes d's list
+func (d *Dropdown[T]) setOpen(open bool) {
+	if d.open != open {
+		d.open = open
+		d.hover = -1
+		d.reshape()
+	}
+}
+
+// optionAt returns the option at ypos while d is open, or -1 if there's none
+func (d *Dropdown[T]) optionAt(ypos float64) int {
+	if !d.open || ypos < d.y+d.height {
+		return -1
+	}
+	i := int((ypos - d.y - d.height) / d.height)
+	if i >= len(d.options) {
+		return -1
+	}
+	return i
+}
+
+// Name returns d's name
+func (d *Dropdown[T]) Name() string {
+	return d.name
+}
+
+// NeedsRedraw returns true if d changed since it was last drawn
+func (d *Dropdown[T]) NeedsRedraw() bool {
+	return d.redraw
+}
+
+// Draw draws the widget, selected determines if the widget displays as selected or not, and forceRedraw
+// forces a full redraw of the widget.
+func (d *Dropdown[T]) Draw(selected, forceRedraw bool) {
+	if d.redraw || forceRedraw {
+		gc := *d.gc
+		gc.Save()
+		fg := textColor(d.theme, d.enabled)
+		gc.SetLineWidth(d.theme.BorderWidth)
+		gc.SetFillColor(d.theme.Background)
+		gc.SetStrokeColor(borderColor(d.theme, selected, d.enabled))
+		gc.FillStroke(d.shape)
+		gc.SetFillColor(fg)
+		fillStringAtWidth(gc, d.format(d.Get()), d.x+d.theme.TextPadding, d.y+d.theme.LineHeight(gc),
+			d.width-d.height-d.theme.TextPadding)
+
+		// Arrow
+		size := d.height / 4
+		cx, cy := d.x+d.width-d.height/2, d.y+d.height/2
+		gc.BeginPath()
+		gc.MoveTo(cx-size, cy-size/2)
+		gc.LineTo(cx+size, cy-size/2)
+		gc.LineTo(cx, cy+size/2)
+		gc.Close()
+		gc.Fill()
+
+		if d.open {
+			for i, o := range d.options {
+				y := d.y + d.height*float64(i+1)
+				if i == d.hover || (d.hover < 0 && o == d.Get()) {
+					gc.SetFillColor(d.theme.Selection)
+					gc.BeginPath()
+					draw2dkit.Rectangle(gc, d.x+d.theme.BorderWidth, y, d.x+d.width-1-d.theme.BorderWidth, y+d.height)
+					gc.Fill()
+				}
+				gc.SetFillColor(fg)
+				fillStringAtWidth(gc, d.format(o), d.x+d.theme.TextPadding, y+d.theme.LineHeight(gc),
+					d.width-d.theme.TextPadding*2)
+			}
+		}
+		gc.Restore()
+
+		d.redraw = false
+	}
+}
+
+// SetTheme restyles d with t
+func (d *Dropdown[T]) SetTheme(t *draw2dui.Theme) {
+	d.theme = t
+	d.height = t.LineHeight(*d.gc) + (t.TextPadding+t.BorderWidth)*2
+	d.reshape()
+}
+
+// Handle closes d once it isn't selected
+func (d *Dropdown[T]) Handle(selected bool) bool {
+	if !selected && d.open {
+		d.setOpen(false)
+		return true
+	}
+	return false
+}
+
+// KeyPress has the widget process a KeyPress event
+func (d *Dropdown[T]) KeyPress(key draw2dui.Key, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if action == draw2dui.Release || !d.enabled {
+		return draw2dui.EventNone
+	}
+	switch key {
+	case draw2dui.KeySpace, draw2dui.KeyEnter:
+		d.setOpen(!d.open)
+		return draw2dui.EventAction
+	case draw2dui.KeyEscape:
+		if d.open {
+			d.setOpen(false)
+			return draw2dui.EventAction
+		}
+	case draw2dui.KeyUp, draw2dui.KeyDown:
+		i := d.Index()
+		if key == draw2dui.KeyUp {
+			i--
+		} else {
+			i++
+		}
+		if i >= 0 && i < len(d.options) {
+			d.Set(d.options[i])
+			return draw2dui.EventAction
+		}
+	}
+	return draw2dui.EventNone
+}
+
+// CharPress returns draw2dui.EventNone
+func (d *Dropdown[T]) CharPress(char rune) draw2dui.Event {
+	return draw2dui.EventNone
+}
+
+// MMove has the widget process a MouseMove event, highlighting the option under the mouse while open
+func (d *Dropdown[T]) MMove(xpos, ypos float64) draw2dui.Event {
+	inside := d.IsInside(xpos, ypos)
+	hover := -1
+	if inside {
+		hover = d.optionAt(ypos)
+	}
+	if hover != d.hover {
+		d.hover = hover
+		d.redraw = true
+	}
+	if d.hasCursor && !inside {
+		d.hasCursor = false
+		return draw2dui.EventAction
+	} else if !inside {
+		return draw2dui.EventNone
+	}
+	if !d.hasCursor {
+		setCursor(d.window, draw2dui.HandCursor)
+		d.hasCursor = true
+	}
+	return draw2dui.EventHasCursor
+}
+
+// MClick has the widget process a MouseClick event. Clicking d opens or closes it, and clicking an option
+// in the open list chooses it. Clicking elsewhere closes it.
+func (d *Dropdown[T]) MClick(xpos, ypos float64, button draw2dui.MouseButton, action draw2dui.Action, mods draw2dui.ModifierKey) draw2dui.Event {
+	if button != draw2dui.MouseButtonLeft || action != draw2dui.Press || !d.enabled {
+		return draw2dui.EventNone
+	}
+	if !d.IsInside(xpos, ypos) {
+		d.setOpen(false)
+		return draw2dui.EventNone
+	}
+	if i := d.optionAt(ypos); i >= 0 {
+		d.Set(d.options[i])
+		d.setOpen(false)
+		return draw2dui.EventAction
+	}
+	d.setOpen(!d.open)
+	return draw2dui.EventSelected
+}
+
+// SetPos changes the widget's x, y coordinates
+func (d *Dropdown[T]) SetPos(x, y float64) {
+	d.x, d.y = x, y
+	d.reshape()
+}
+
+// GetPos retrieves the widget's x, y coordinates
+func (d *Dropdown[T]) GetPos() (float64, float64) {
+	return d.x, d.y
+}
+
+// NaturalSize returns the width and height d needs while closed: the width it was created with, or wider to
+// fit its widest option and arrow
+func (d *Dropdown[T]) NaturalSize() (width, height float64) {
+	height = d.theme.LineHeight(*d.gc) + (d.theme.TextPadding+d.theme.BorderWidth)*2
+	width = d.minWidth
+	for _, o := range d.options {
+		_, _, w, _ := (*d.gc).GetStringBounds(d.format(o))
+		width = math.Max(width, w+d.theme.TextPadding*2+height)
+	}
+	return
+}
+
+// SetDimensions sets d's drawn width and its height while closed
+func (d *Dropdown[T]) SetDimensions(w, h float64) {
+	d.width, d.height = w, h
+	d.reshape()
+}
+
+// GetDimensions returns d's drawn width and height, which includes the list while it's open
+func (d *Dropdown[T]) GetDimensions() (float64, float64) {
+	if d.open {
+		return d.width, d.height * float64(len(d.options)+1)
+	}
+	return d.width, d.height
+}
+
+// IsInside checks if point x, y is inside of the widget's boundaries
+func (d *Dropdown[T]) IsInside(x, y float64) bool {
+	return draw2dui.IsPointInPath(d.shape, x, y)
+}
+
+// SetEnabled enables or disables the widget
+func (d *Dropdown[T]) SetEnabled(enabled bool) {
+	if d.enabled != enabled {
+		d.enabled = enabled
+		d.setOpen(false)
+		d.redraw = true
+	}
+}
+
+// GetEnabled returns whether the widget is enabled or not
+func (d *Dropdown[T]) GetEnabled() bool {
+	return d.enabled
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2016, redstarcoder
+package widgets
+
+import (
+	"image/color"
+	"reflect"
+
+	"github.com/redstarcoder/draw2dui"
+)
+
+// defaultTheme styles widgets until they're given a Theme, usually by a draw2dui.WidgetCollection
+var defaultTheme = draw2dui.DefaultTheme()
+
+// setCursor sets window's cursor to shape. It does nothing if window is nil or a nil pointer, so widgets can
+// run headless.
+func setCursor(window draw2dui.CursorSetter, shape draw2dui.CursorShape) {
+	if window == nil {
+		return
+	}
+	if v := reflect.ValueOf(window); v.Kind() == reflect.Ptr && v.IsNil() {
+		return
+	}
+	window.SetCursor(shape)
+}
+
+// borderColor returns the color of a widget's border in theme
+func borderColor(theme *draw2dui.Theme, selected, enabled bool) color.RGBA {
+	if !enabled {
+		return theme.Disabled
+	} else if selected {
+		return theme.Focus
+	}
+	return theme.Border
+}
+
+// backgroundColor returns the color behind a widget's text in theme, which is highlighted while the widget is
+// the target of a drag and drop
+func backgroundColor(theme *draw2dui.Theme, dropTarget bool) color.RGBA {
+	if dropTarget {
+		return theme.Selection
+	}
+	return theme.Background
+}
+
+// textColor returns the color of a widget's text in theme
+func textColor(theme *draw2dui.Theme, enabled bool) color.RGBA {
+	if !enabled {
+		return theme.Disabled
+	}
+	return theme.Foreground
+}
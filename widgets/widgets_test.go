@@ -0,0 +1,847 @@
+// Copyright (c) 2016, redstarcoder
+package widgets
+
+import (
+	"image/color"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/llgcode/draw2d"
+	"github.com/redstarcoder/draw2dui"
+)
+
+// getHeadlessContext returns an ImageContext with the test font loaded, and a pointer to it for widgets
+func getHeadlessContext() (*draw2dui.ImageContext, *draw2d.GraphicContext) {
+	ic := draw2dui.NewImageContext(200, 200)
+	ic.SetFontData(draw2d.FontData{
+		Name:   "luxi",
+		Family: draw2d.FontFamilySerif,
+		Style:  draw2d.FontStyleNormal})
+	ic.SetFontSize(12)
+	var gc draw2d.GraphicContext = ic
+	return ic, &gc
+}
+
+// drewInside checks if anything that isn't white was drawn inside of w's boundaries
+func drewInside(ic *draw2dui.ImageContext, w draw2dui.Widget) bool {
+	x, y := w.GetPos()
+	width, height := w.GetDimensions()
+	for px := int(x); px < int(x+width); px++ {
+		for py := int(y); py < int(y+height); py++ {
+			if ic.Image.At(px, py) != (color.RGBA{255, 255, 255, 0xff}) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestHeadlessDraw(t *testing.T) {
+	ic, gc := getHeadlessContext()
+	for _, w := range []draw2dui.Widget{
+		NewButton(gc, nil, 10, 10, "Button"),
+		NewLabel(gc, nil, 10, 40, "Label"),
+		NewTextField(gc, nil, 10, 70, 150, "TextField", 20),
+		NewTextBox(gc, nil, 10, 100, 150, 80, "TextBox\nLine 2"),
+		NewCheckbox(gc, nil, 100, 10, "Checkbox", true),
+		NewSlider(gc, nil, 100, 40, 90, 0, 1, 0.5),
+	} {
+		w.Draw(true, true)
+		if !drewInside(ic, w) {
+			t.Errorf("%s didn't draw anything.", w.Name())
+		}
+	}
+}
+
+func TestHeadlessEvents(t *testing.T) {
+	_, gc := getHeadlessContext()
+	tf := NewTextField(gc, nil, 10, 10, 150, "", 20)
+	if tf.MMove(20, 15) != draw2dui.EventHasCursor {
+		t.Error("TextField should take the cursor.")
+	}
+	if tf.MMove(5, 5) != draw2dui.EventNone {
+		t.Error("TextField shouldn't take the cursor.")
+	}
+	if tf.CharPress('a') != draw2dui.EventAction || tf.GetString() != "a" {
+		t.Error("TextField should accept characters.")
+	}
+	if tf.KeyPress(draw2dui.KeyBackspace, draw2dui.Press, 0) != draw2dui.EventAction || tf.GetString() != "" {
+		t.Error("TextField should handle backspace.")
+	}
+	if tf.KeyPress(draw2dui.KeyEnter, draw2dui.Release, 0) != draw2dui.EventNone {
+		t.Error("TextField should ignore releases.")
+	}
+}
+
+func TestPanel(t *testing.T) {
+	ic, gc := getHeadlessContext()
+	tf := NewTextField(gc, nil, 10, 10, 60, "", 20)
+	inner := NewPanel(gc, nil, 10, 40, 80, 40, tf)
+	outer := NewPanel(gc, nil, 50, 50, 100, 100, inner)
+	wc := draw2dui.NewWidgetCollection(gc, nil, outer)
+
+	// tf is at 70, 100 on the screen
+	if w, _ := wc.MMove(75, 105); w != outer || inner.Children().Get(tf.Name()) != tf {
+		t.Error("outer should take the cursor.")
+	}
+	wc.MClick(draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	if wc.Selected() != outer || outer.Children().Selected() != inner || inner.Children().Selected() != tf {
+		t.Error("Clicking should select tf.")
+	}
+	wc.CharPress('a')
+	if tf.GetString() != "a" {
+		t.Error("tf should receive characters.")
+	}
+
+	wc.Draw()
+	if !drewInside(ic, outer) || ic.Image.At(70, 105) == ic.Image.At(45, 45) {
+		t.Error("outer and its children should be drawn.")
+	}
+	tf.SetPos(60, 10)
+	wc.Draw()
+	if ic.Image.At(145, 105) != ic.Image.At(45, 45) || ic.Image.At(141, 100) != ic.Image.At(10, 10) {
+		t.Error("inner's children should be clipped to inner.")
+	}
+
+	wc.MClick(draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	wc.MMove(10, 10)
+	wc.MClick(draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	wc.Handle()
+	if outer.Children().Selected() != nil {
+		t.Error("Deselecting outer should deselect its children.")
+	}
+}
+
+func TestPanelBorder(t *testing.T) {
+	ic, gc := getHeadlessContext()
+	tf := NewTextField(gc, nil, 0, 0, 60, "", 20)
+	p := NewPanel(gc, nil, 20, 20, 100, 100, tf)
+	wc := draw2dui.NewWidgetCollection(gc, nil, p)
+	wc.Draw()
+	// the inside half of p's border is at x 20
+	if ic.Image.At(20, 100) == (color.RGBA{255, 255, 255, 0xff}) {
+		t.Fatal("p's border shouldn't be covered by its children.")
+	}
+
+	border := ic.Image.At(20, 30)
+	tf.SetString("redraw")
+	wc.Draw()
+	if ic.Image.At(20, 30) != border {
+		t.Error("Redrawing p's children shouldn't cover its border.")
+	}
+	ic.Image.Set(100, 100, color.Black)
+	tf.SetString("again")
+	wc.Draw()
+	if ic.Image.At(100, 100) != (color.RGBA{0, 0, 0, 0xff}) {
+		t.Error("Redrawing p's children shouldn't redraw all of p.")
+	}
+}
+
+func TestPanelTab(t *testing.T) {
+	_, gc := getHeadlessContext()
+	before := NewButton(gc, nil, 0, 0, "Before")
+	tf1 := NewTextField(gc, nil, 10, 10, 60, "", 20)
+	tf2 := NewTextField(gc, nil, 10, 40, 60, "", 20)
+	p := NewPanel(gc, nil, 0, 30, 100, 80, tf1, tf2)
+	after := NewButton(gc, nil, 0, 120, "After")
+	wc := draw2dui.NewWidgetCollection(gc, nil, before, p, after)
+	selected := 0
+	p.OnSelected = func(draw2dui.Widget) { selected++ }
+
+	tab := func(mods draw2dui.ModifierKey, want draw2dui.Widget, child draw2dui.Widget) {
+		t.Helper()
+		wc.KeyPress(draw2dui.KeyTab, draw2dui.Press, mods)
+		wc.Handle()
+		if wc.Selected() != want || p.Children().Selected() != child {
+			t.Errorf("Tab selected %v and %v, should have selected %s and %v.", wc.Selected(),
+				p.Children().Selected(), want.Name(), child)
+		}
+	}
+	tab(0, p, tf1)
+	tab(0, p, tf2)
+	tab(0, after, nil)
+	tab(draw2dui.ModShift, p, tf2)
+	tab(draw2dui.ModShift, p, tf1)
+	tab(draw2dui.ModShift, before, nil)
+	if selected != 2 {
+		t.Errorf("p's OnSelected was called %d times, should be called once each time p is tabbed into.", selected)
+	}
+}
+
+func TestTheme(t *testing.T) {
+	ic, gc := getHeadlessContext()
+	tf := NewTextField(gc, nil, 10, 10, 60, "", 20)
+	btn := NewButton(gc, nil, 10, 40, "Button")
+	wc := draw2dui.NewWidgetCollection(gc, nil, tf, btn)
+	wc.Reshape(200, 200)
+	wc.Draw()
+	if ic.Image.At(20, 15) != defaultTheme.Background {
+		t.Error("tf should be drawn with the default theme.")
+	}
+
+	dark := draw2dui.DarkTheme()
+	dark.FontSize = 20
+	dark.ButtonPadding = 10
+	wc.SetTheme(dark)
+	if (*gc).GetFontSize() != 20 {
+		t.Error("SetTheme should apply the theme's font size.")
+	}
+	if _, h := btn.GetDimensions(); h != 20+dark.LineSpacing+22 {
+		t.Errorf("btn's height is %v, should be %v.", h, 20+dark.LineSpacing+22)
+	}
+	wc.Draw()
+	for _, p := range [][2]int{{20, 15}, {5, 5}, {190, 190}} {
+		if ic.Image.At(p[0], p[1]) != dark.Background {
+			t.Errorf("%v should be drawn with the dark theme.", p)
+		}
+	}
+}
+
+func TestThemeLayout(t *testing.T) {
+	_, gc := getHeadlessContext()
+	btn1 := NewButton(gc, nil, 0, 0, "Button 1")
+	btn2 := NewButton(gc, nil, 0, 0, "Button 2")
+	wc := draw2dui.NewWidgetCollection(gc, nil, btn1, btn2)
+	wc.Reshape(200, 200)
+	wc.SetLayout(draw2dui.NewVBox(0, 0).Add(btn1, 0).Add(btn2, 0))
+
+	dark := draw2dui.DarkTheme()
+	dark.FontSize = 20
+	wc.SetTheme(dark)
+	_, h := btn1.GetDimensions()
+	if _, y := btn2.GetPos(); y != h {
+		t.Errorf("btn2 is at %v, should be moved below btn1's new height, %v.", y, h)
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	_, gc := getHeadlessContext()
+	for _, c := range []struct {
+		w                       draw2dui.Widget
+		text, integer, disables bool
+	}{
+		{NewButton(gc, nil, 0, 0, "Button"), true, false, true},
+		{NewLabel(gc, nil, 0, 0, "Label"), true, false, false},
+		{NewTextField(gc, nil, 0, 0, 50, "", 10), true, true, true},
+		{NewTextBox(gc, nil, 0, 0, 50, 50, ""), true, true, true},
+		{NewPanel(gc, nil, 0, 0, 50, 50), false, false, true},
+		{NewCheckbox(gc, nil, 0, 0, "Checkbox", false), true, false, true},
+		{NewSlider(gc, nil, 0, 0, 50, 0, 1, 0), false, false, true},
+		{NewDropdown(gc, nil, 0, 0, 50, []int{1, 2}, 1), false, false, true},
+	} {
+		_, text := c.w.(draw2dui.TextValue)
+		_, integer := c.w.(draw2dui.IntValue)
+		_, disables := c.w.(draw2dui.Enabler)
+		if _, data := c.w.(draw2dui.DataValue); data || text != c.text || integer != c.integer ||
+			disables != c.disables {
+			t.Errorf("%s supports the wrong interfaces.", c.w.Name())
+		}
+	}
+}
+
+func TestStretch(t *testing.T) {
+	_, gc := getHeadlessContext()
+	btn := NewButton(gc, nil, 0, 0, "Button")
+	lbl := NewLabel(gc, nil, 0, 0, "Label")
+	cb := NewCheckbox(gc, nil, 0, 0, "Checkbox", false)
+	box := draw2dui.NewVBox(0, 0).Add(btn, 0).Add(lbl, 0).Add(cb, 0)
+	box.Arrange(0, 0, 180, 200)
+	for _, w := range []draw2dui.Widget{btn, lbl, cb} {
+		x, y := w.GetPos()
+		if width, _ := w.GetDimensions(); width != 180 || !w.IsInside(x+170, y+5) {
+			t.Errorf("%s is %v wide, should be stretched to 180.", w.Name(), width)
+		}
+	}
+	lbl.SetString("L")
+	if width, _ := lbl.GetDimensions(); width >= 180 {
+		t.Error("Changing lbl's text should fit it to the text again.")
+	}
+
+	row := draw2dui.NewHBox(0, 0).Add(lbl, 0).Add(btn, 0)
+	row.Arrange(0, 0, 400, 50)
+	x, _ := btn.GetPos()
+	lbl.SetString("A much longer label")
+	row.Arrange(0, 0, 400, 50)
+	if x2, _ := btn.GetPos(); x2 <= x {
+		t.Error("Arranging should make room for lbl's longer text.")
+	}
+}
+
+func TestValueWidgets(t *testing.T) {
+	_, gc := getHeadlessContext()
+	cb := NewCheckbox(gc, nil, 10, 10, "Checkbox", false)
+	changes := 0
+	cb.OnChange(func(bool) { changes++ })
+	cb.MClick(15, 15, draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	if v, ok := draw2dui.GetValue[bool](cb); !ok || !v || changes != 1 {
+		t.Error("Clicking cb should check it.")
+	}
+	if cb.KeyPress(draw2dui.KeySpace, draw2dui.Press, 0) != draw2dui.EventAction || cb.Get() || changes != 2 {
+		t.Error("Space should uncheck cb.")
+	}
+
+	// s's track runs from 18.5 to 101.5
+	s := NewSlider(gc, nil, 10, 40, 100, 0, 10, 5)
+	s.Set(20)
+	if s.Get() != 10 {
+		t.Errorf("s is %v, should be clamped to 10.", s.Get())
+	}
+	if s.Set(math.NaN()); s.Get() != 10 {
+		t.Errorf("s is %v, NaN should be ignored.", s.Get())
+	}
+	s.SetStep(2.5)
+	if s.Set(3.5); s.Get() != 2.5 {
+		t.Errorf("s is %v, should be rounded to 2.5.", s.Get())
+	}
+	if s.KeyPress(draw2dui.KeyRight, draw2dui.Press, 0); s.Get() != 5 {
+		t.Errorf("s is %v, should be moved to 5.", s.Get())
+	}
+	if s.MClick(18.5, 45, draw2dui.MouseButtonLeft, draw2dui.Press, 0) != draw2dui.EventSelected || s.Get() != 0 {
+		t.Errorf("s is %v, clicking should move it to 0.", s.Get())
+	}
+	s.MMove(200, 200)
+	s.MClick(200, 200, draw2dui.MouseButtonLeft, draw2dui.Release, 0)
+	s.MMove(18.5, 45)
+	if s.Get() != 10 {
+		t.Errorf("s is %v, dragging should move it to 10.", s.Get())
+	}
+	s.MClick(18.5, 45, draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	if !s.Handle(false) {
+		t.Error("s should be redrawn when it stops being dragged.")
+	}
+	if s.MMove(101.5, 45); s.Get() != 0 {
+		t.Errorf("s is %v, deselecting it should stop dragging.", s.Get())
+	}
+
+	// Each of d's rows is 19 high
+	d := NewDropdown(gc, nil, 10, 100, 80, []string{"a", "b", "c"}, "b")
+	var chosen string
+	d.OnChange(func(v string) { chosen = v })
+	d.MClick(20, 105, draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	if _, h := d.GetDimensions(); h != 76 {
+		t.Errorf("Open d is %v high, should be 76.", h)
+	}
+	d.MClick(20, 162, draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	if _, h := d.GetDimensions(); chosen != "c" || d.Index() != 2 || h != 19 {
+		t.Error("Clicking an option should choose it and close d.")
+	}
+	if d.KeyPress(draw2dui.KeyUp, draw2dui.Press, 0); chosen != "b" {
+		t.Error("Up should choose the previous option.")
+	}
+	if !draw2dui.SetValue(d, "a") || chosen != "a" {
+		t.Error("SetValue should choose an option.")
+	}
+	if draw2dui.SetValue(d, 1) {
+		t.Error("SetValue should fail with the wrong type.")
+	}
+	d.MClick(20, 105, draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	if d.MMove(20, 143); d.hover != 1 {
+		t.Errorf("d's hovered option is %d, should be 1.", d.hover)
+	}
+	if d.MMove(200, 200); d.hover != -1 {
+		t.Error("Moving the mouse off of d should stop highlighting its option.")
+	}
+}
+
+func TestSliderRange(t *testing.T) {
+	_, gc := getHeadlessContext()
+	defer func() {
+		if recover() == nil {
+			t.Error("A Slider with min greater than max should panic.")
+		}
+	}()
+	NewSlider(gc, nil, 0, 0, 100, 10, 0, 5)
+}
+
+func TestNextDeadline(t *testing.T) {
+	_, gc := getHeadlessContext()
+	clock := draw2dui.NewFakeClock(time.Now())
+	tf := NewTextField(gc, nil, 10, 10, 60, "", 20)
+	panel := NewPanel(gc, nil, 0, 0, 100, 100, tf)
+	wc := draw2dui.NewWidgetCollection(gc, nil, panel)
+	wc.SetClock(clock)
+	blinked := func() bool {
+		redraw := wc.Handle()
+		wc.Draw()
+		return redraw
+	}
+	if _, ok := tf.NextDeadline(false); ok {
+		t.Error("tf's cursor shouldn't blink while it isn't selected.")
+	}
+	if !blinked() || blinked() {
+		t.Error("tf's cursor should blink once.")
+	}
+	if d, ok := wc.NextDeadline(); !ok || !d.Equal(clock.Now().Add(blinkInterval)) {
+		t.Errorf("tf's next blink is at %v, should be at %v.", d, clock.Now().Add(blinkInterval))
+	}
+	clock.Advance(blinkInterval - time.Millisecond)
+	if blinked() {
+		t.Error("tf's cursor shouldn't blink before its deadline.")
+	}
+	clock.Advance(time.Millisecond)
+	if !wc.Handle() || !tf.NeedsRedraw() {
+		t.Error("tf's cursor should blink at its deadline.")
+	}
+}
+
+func TestTextBoxScroll(t *testing.T) {
+	_, gc := getHeadlessContext()
+	tb := NewTextBox(gc, nil, 10, 10, 100, 80, "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15")
+	panel := NewPanel(gc, nil, 0, 0, 150, 150, tb)
+	wc := draw2dui.NewWidgetCollection(gc, nil, panel)
+	wc.MMove(50, 50)
+
+	// Lines are 15 high, so the last line is 14 lines from the first
+	for _, c := range []struct {
+		yoff  float64
+		iY    int
+		frac  float64
+		event draw2dui.Event
+	}{
+		{1, scrollLines, 0, draw2dui.EventAction},
+		{0.1, scrollLines, 4.5, draw2dui.EventAction},
+		{-0.1, scrollLines, 0, draw2dui.EventAction},
+		{10, 14, 0, draw2dui.EventAction},
+		{1, 14, 0, draw2dui.EventNone},
+		{-10, 0, 0, draw2dui.EventAction},
+	} {
+		w, event := wc.Scroll(0, c.yoff)
+		if event != c.event || (event != draw2dui.EventNone && w != panel) || tb.cursor.iY != c.iY ||
+			math.Abs(tb.scrollFrac-c.frac) > 1e-6 {
+			t.Errorf("Scrolling by %v returned %v and scrolled to line %v plus %v, should be %v and %v plus %v.",
+				c.yoff, event, tb.cursor.iY, tb.scrollFrac, c.event, c.iY, c.frac)
+		}
+	}
+}
+
+func TestDrop(t *testing.T) {
+	_, gc := getHeadlessContext()
+	tf := NewTextField(gc, nil, 10, 10, 100, "old", 100)
+	tb := NewTextBox(gc, nil, 10, 40, 100, 80, "ab")
+	panel := NewPanel(gc, nil, 0, 0, 150, 150, tf, tb)
+	wc := draw2dui.NewWidgetCollection(gc, nil, panel)
+
+	path := filepath.Join(t.TempDir(), "dropped.txt")
+	if err := ioutil.WriteFile(path, []byte("xyz"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if w := wc.DragOver(20, 15, []string{path}); w != panel || !tf.dropTarget {
+		t.Error("TextField should be highlighted while files are dragged over it.")
+	}
+	wc.DragOver(20, 60, []string{path})
+	if tf.dropTarget || !tb.dropTarget {
+		t.Error("Highlights should move with the drag.")
+	}
+	if _, event := wc.Drop(20, 15, []string{path}); event != draw2dui.EventAction || tf.GetString() != path ||
+		tf.GetInt() != len(path) || tf.dropTarget || tb.dropTarget {
+		t.Errorf("TextField holds %q after a drop, it should hold the dropped path.", tf.GetString())
+	}
+
+	tb.cursor.i, tb.cursor.anchor = 1, 1
+	if _, event := wc.Drop(20, 60, []string{path, path + ".missing"}); event != draw2dui.EventAction ||
+		tb.GetString() != "axyzb" || tb.cursor.i != 4 {
+		t.Errorf("TextBox holds %q after a drop, it should hold the file's contents at the cursor.", tb.GetString())
+	}
+	tb.maxlen = 6
+	if _, event := wc.Drop(20, 60, []string{path}); event != draw2dui.EventNone || tb.GetString() != "axyzb" {
+		t.Error("Files which don't fit in TextBox's maxlen shouldn't be inserted.")
+	}
+	tb.maxlen = 7
+	tb.cursor.i, tb.cursor.anchor = 5, 4
+	if wc.Drop(20, 60, []string{path}); tb.GetString() != "axyzxyz" || tb.cursor.i != 7 {
+		t.Errorf("TextBox holds %q after a drop, it should replace the selection.", tb.GetString())
+	}
+	if !tb.cursor.Undo() || tb.GetString() != "axyzb" {
+		t.Error("A drop should be undone in one step.")
+	}
+}
+
+func TestClipboardShortcuts(t *testing.T) {
+	_, gc := getHeadlessContext()
+	tf := NewTextField(gc, nil, 10, 10, 100, "abc", 8)
+	tb := NewTextBox(gc, nil, 10, 40, 100, 80, "xy")
+	clipboard := &draw2dui.MemoryClipboard{}
+	wc := draw2dui.NewWidgetCollection(gc, nil, NewPanel(gc, nil, 0, 0, 150, 150, tf, tb))
+	wc.SetClipboard(clipboard)
+	press := func(w draw2dui.Widget, key draw2dui.Key) draw2dui.Event {
+		return w.KeyPress(key, draw2dui.Press, draw2dui.ModControl)
+	}
+
+	if event := press(tf, draw2dui.KeyC); event != draw2dui.EventNone || clipboard.ClipboardString() != "" {
+		t.Error("Copying without a selection shouldn't change the clipboard.")
+	}
+	if event := press(tf, draw2dui.KeyX); event != draw2dui.EventNone || tf.GetString() != "abc" ||
+		clipboard.ClipboardString() != "" {
+		t.Error("Cutting without a selection shouldn't change TextField's text or the clipboard.")
+	}
+	press(tf, draw2dui.KeyA)
+	if event := press(tf, draw2dui.KeyC); event != draw2dui.EventNone || clipboard.ClipboardString() != "abc" {
+		t.Errorf("Copying put %q on the clipboard, should put %q.", clipboard.ClipboardString(), "abc")
+	}
+	if event := press(tf, draw2dui.KeyX); event != draw2dui.EventAction || tf.GetString() != "" ||
+		tf.GetInt() != 0 || clipboard.ClipboardString() != "abc" {
+		t.Error("Cutting should move TextField's text to the clipboard.")
+	}
+	clipboard.SetClipboardString("1\x072\r345678")
+	tf.SetString("ab")
+	tf.SetInt(1)
+	if event := press(tf, draw2dui.KeyV); event != draw2dui.EventAction || tf.GetString() != "a12 345b" ||
+		tf.GetInt() != 7 {
+		t.Errorf("TextField holds %q after pasting, should hold %q cut short to its maxlen.", tf.GetString(),
+			"a12 345b")
+	}
+	if event := press(tf, draw2dui.KeyV); event != draw2dui.EventNone {
+		t.Error("Pasting into a full TextField should do nothing.")
+	}
+	if event := tf.KeyPress(draw2dui.KeyV, draw2dui.Press, 0); event != draw2dui.EventNone {
+		t.Error("V without Ctrl shouldn't paste.")
+	}
+
+	clipboard.SetClipboardString("1\r\n\x002\t")
+	tb.cursor.i, tb.cursor.anchor = 1, 1
+	if event := press(tb, draw2dui.KeyV); event != draw2dui.EventAction || tb.GetString() != "x1\n2y" ||
+		len(tb.cursor.textLines) != 2 {
+		t.Errorf("TextBox holds %q after pasting, should hold %q.", tb.GetString(), "x1\n2y")
+	}
+	if event := press(tb, draw2dui.KeyX); event != draw2dui.EventNone || tb.GetString() != "x1\n2y" {
+		t.Error("Cutting without a selection shouldn't change TextBox's text.")
+	}
+	press(tb, draw2dui.KeyA)
+	if event := press(tb, draw2dui.KeyX); event != draw2dui.EventAction || tb.GetString() != "" ||
+		clipboard.ClipboardString() != "x1\n2y" {
+		t.Error("Cutting should move TextBox's text to the clipboard.")
+	}
+}
+
+func TestTextSelection(t *testing.T) {
+	ic, gc := getHeadlessContext()
+	tf := NewTextField(gc, nil, 10, 10, 100, "hello world", 20)
+	tf.SetInt(len(tf.GetString()))
+	press := func(w draw2dui.Widget, key draw2dui.Key, mods draw2dui.ModifierKey) {
+		w.KeyPress(key, draw2dui.Press, mods)
+	}
+	press(tf, draw2dui.KeyLeft, draw2dui.ModShift)
+	press(tf, draw2dui.KeyLeft, draw2dui.ModShift)
+	if s := tf.cursor.SelectedText(); s != "ld" {
+		t.Errorf("Shift+Left selected %q, should select %q.", s, "ld")
+	}
+	tf.Draw(true, true)
+	start, _ := tf.cursor.Selection()
+	x := tf.x + tf.theme.TextPadding + stringWidth(*gc, tf.GetString()[tf.cursor.iOffset:start]) + 1
+	if ic.Image.RGBAAt(int(x), int(tf.y+tf.theme.LineHeight(*gc))+1) != tf.theme.Selection {
+		t.Error("The selection should be drawn with the theme's Selection color.")
+	}
+	if tf.CharPress('X') != draw2dui.EventAction || tf.GetString() != "hello worX" {
+		t.Errorf("TextField holds %q after typing over the selection, should hold %q.", tf.GetString(),
+			"hello worX")
+	}
+	press(tf, draw2dui.KeyLeft, draw2dui.ModShift)
+	press(tf, draw2dui.KeyLeft, 0)
+	if tf.cursor.SelectedText() != "" || tf.GetInt() != 9 {
+		t.Error("Left without Shift should clear the selection, leaving the cursor at its start.")
+	}
+	press(tf, draw2dui.KeyA, draw2dui.ModControl)
+	if tf.KeyPress(draw2dui.KeyBackspace, draw2dui.Press, 0) != draw2dui.EventAction || tf.GetString() != "" {
+		t.Error("Backspace should delete all of the text after selecting it all.")
+	}
+
+	tf.SetString("abc")
+	tf.MClick(10, 15, draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	tf.MMove(200, 15)
+	tf.MClick(150, 15, draw2dui.MouseButtonLeft, draw2dui.Release, 0)
+	tf.MMove(10, 15)
+	if s := tf.cursor.SelectedText(); s != "abc" {
+		t.Errorf("Dragging the mouse selected %q, should select %q.", s, "abc")
+	}
+	padded := draw2dui.DefaultTheme()
+	padded.TextPadding = 20
+	tf.SetTheme(padded)
+	if tf.MClick(32, 15, draw2dui.MouseButtonLeft, draw2dui.Press, 0); tf.GetInt() != 0 {
+		t.Errorf("Clicking before the first character moved the cursor to %d, should move it to 0.", tf.GetInt())
+	}
+	tf.MClick(32, 15, draw2dui.MouseButtonLeft, draw2dui.Release, 0)
+	tf.SetTheme(defaultTheme)
+
+	// Lines are drawn upwards from the bottom of the TextBox, so "ab" is drawn a line above "cd"
+	tb := NewTextBox(gc, nil, 10, 40, 100, 80, "ab\ncd")
+	clipboard := &draw2dui.MemoryClipboard{}
+	tb.SetClipboard(clipboard)
+	bottom := tb.y + float64(tb.cursor.maxLines)*tb.theme.LineHeight(*gc)
+	tb.MClick(11, bottom-20, draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	tb.MMove(200, bottom-5)
+	tb.MClick(200, bottom-5, draw2dui.MouseButtonLeft, draw2dui.Release, 0)
+	press(tb, draw2dui.KeyC, draw2dui.ModControl)
+	if s := clipboard.ClipboardString(); s != "ab\ncd" {
+		t.Errorf("Dragging the mouse over TextBox selected %q, should select %q.", s, "ab\ncd")
+	}
+	press(tb, draw2dui.KeyLeft, draw2dui.ModShift)
+	if tb.CharPress('x') != draw2dui.EventAction || tb.GetString() != "xd" || len(tb.cursor.textLines) != 1 {
+		t.Errorf("TextBox holds %q after typing over the selection, should hold %q.", tb.GetString(), "xd")
+	}
+}
+
+func TestUndo(t *testing.T) {
+	_, gc := getHeadlessContext()
+	tf := NewTextField(gc, nil, 10, 10, 100, "", 20)
+	press := func(w draw2dui.Widget, key draw2dui.Key, mods draw2dui.ModifierKey) draw2dui.Event {
+		return w.KeyPress(key, draw2dui.Press, mods)
+	}
+	undo := func(w draw2dui.Widget) draw2dui.Event { return press(w, draw2dui.KeyZ, draw2dui.ModControl) }
+	for _, r := range "abcd" {
+		tf.CharPress(r)
+	}
+	press(tf, draw2dui.KeyLeft, 0)
+	tf.CharPress('X')
+	if undo(tf) != draw2dui.EventAction || tf.GetString() != "abcd" || tf.GetInt() != 3 {
+		t.Errorf("TextField holds %q after undoing, should hold %q.", tf.GetString(), "abcd")
+	}
+	if undo(tf) != draw2dui.EventAction || tf.GetString() != "" {
+		t.Errorf("TextField holds %q after undoing, characters typed together should be undone together.",
+			tf.GetString())
+	}
+	if undo(tf) != draw2dui.EventNone {
+		t.Error("There should be nothing left to undo.")
+	}
+	if press(tf, draw2dui.KeyY, draw2dui.ModControl) != draw2dui.EventAction || tf.GetString() != "abcd" {
+		t.Error("Ctrl+Y should redo.")
+	}
+	if press(tf, draw2dui.KeyZ, draw2dui.ModControl|draw2dui.ModShift) != draw2dui.EventAction ||
+		tf.GetString() != "abcXd" {
+		t.Error("Ctrl+Shift+Z should redo.")
+	}
+	tf.KeyPress(draw2dui.KeyBackspace, draw2dui.Press, 0)
+	tf.SetString("new")
+	if undo(tf); tf.GetString() != "abcd" {
+		t.Errorf("TextField holds %q after undoing SetString, should hold %q.", tf.GetString(), "abcd")
+	}
+	if undo(tf); tf.GetString() != "abcXd" {
+		t.Errorf("TextField holds %q after undoing Backspace, should hold %q.", tf.GetString(), "abcXd")
+	}
+	if press(tf, draw2dui.KeyY, draw2dui.ModControl); tf.GetString() != "abcd" {
+		t.Error("Redoing should work after undoing several edits.")
+	}
+	tf.CharPress('e')
+	if press(tf, draw2dui.KeyY, draw2dui.ModControl) != draw2dui.EventNone {
+		t.Error("Edits should clear what can be redone.")
+	}
+
+	tf.SetHistoryLength(1)
+	tf.SetString("one")
+	tf.SetString("two")
+	if undo(tf); undo(tf) != draw2dui.EventNone || tf.GetString() != "one" {
+		t.Error("Only the last edit should be undone with a history length of 1.")
+	}
+
+	tf.cursor.history.undo = append(tf.cursor.history.undo, snapshot{"e\u0301", 1, 9})
+	if undo(tf); tf.GetInt() != 0 || tf.cursor.anchor != 3 {
+		t.Errorf("Undoing moved the cursor to %d and anchor to %d, they should be clamped onto characters.",
+			tf.GetInt(), tf.cursor.anchor)
+	}
+
+	tb := NewTextBox(gc, nil, 10, 40, 100, 80, "a")
+	tb.SetString("b\nc")
+	if undo(tb) != draw2dui.EventAction || tb.GetString() != "a" || len(tb.cursor.textLines) != 1 {
+		t.Errorf("TextBox holds %q after undoing, should hold %q.", tb.GetString(), "a")
+	}
+}
+
+func TestCharBoundaries(t *testing.T) {
+	for _, c := range []struct {
+		s     string
+		chars []string
+	}{
+		{"abc", []string{"a", "b", "c"}},
+		{"e\u0301te\u0301", []string{"e\u0301", "t", "e\u0301"}},
+		{"日本語", []string{"日", "本", "語"}},
+		{"a\r\nb", []string{"a", "\r\n", "b"}},
+		{"a\u200d\n\u0301\r\u200d", []string{"a\u200d", "\n", "\u0301", "\r", "\u200d"}},
+		{"\U0001F44D\U0001F3FD!", []string{"\U0001F44D\U0001F3FD", "!"}},
+		{"\U0001F468\u200d\U0001F469\u200d\U0001F467", []string{"\U0001F468\u200d\U0001F469\u200d\U0001F467"}},
+		{"\U0001F1E8\U0001F1E6\U0001F1EB\U0001F1F7\U0001F1EF", []string{"\U0001F1E8\U0001F1E6", "\U0001F1EB\U0001F1F7", "\U0001F1EF"}},
+	} {
+		var forward []string
+		for i := 0; i < len(c.s); {
+			next := nextBoundary(c.s, i)
+			forward = append(forward, c.s[i:next])
+			i = next
+		}
+		var backward []string
+		for i := len(c.s); i > 0; {
+			prev := prevBoundary(c.s, i)
+			backward = append([]string{c.s[prev:i]}, backward...)
+			i = prev
+		}
+		if strings.Join(forward, "|") != strings.Join(c.chars, "|") ||
+			strings.Join(backward, "|") != strings.Join(c.chars, "|") {
+			t.Errorf("%q was split into %q forwards and %q backwards, should be %q.", c.s, forward, backward, c.chars)
+		}
+	}
+	if s := truncate("ae\u0301b", 2); s != "ae\u0301" || charCount(s) != 2 {
+		t.Errorf("truncate cut %q to %q, accents shouldn't be split from their letters.", "ae\u0301b", s)
+	}
+	if i := charStart("a日e\u0301", 2); i != 1 {
+		t.Errorf("charStart found %d inside of 日, should find 1.", i)
+	}
+	if i := charStart("ae\u0301\u0301b", 4); i != 1 {
+		t.Errorf("charStart found %d inside of an accented e, should find 1.", i)
+	}
+}
+
+func BenchmarkPrevBoundary(b *testing.B) {
+	s := strings.Repeat("ae\u0301", 10000)
+	for n := 0; n < b.N; n++ {
+		for i := len(s); i > 0; i = prevBoundary(s, i) {
+		}
+	}
+}
+
+func TestUnicodeEditing(t *testing.T) {
+	_, gc := getHeadlessContext()
+	tf := NewTextField(gc, nil, 10, 10, 150, "", 3)
+	for _, r := range "é日e\u0301" {
+		tf.CharPress(r)
+	}
+	if tf.GetString() != "é日e\u0301" {
+		t.Errorf("TextField holds %q, should hold %q.", tf.GetString(), "é日e\u0301")
+	}
+	if tf.CharPress('x') != draw2dui.EventNone {
+		t.Error("TextField's maxlen should count characters, not bytes.")
+	}
+	tf.KeyPress(draw2dui.KeyLeft, draw2dui.Press, 0)
+	if tf.GetInt() != len("é日") {
+		t.Error("Left should move over a letter along with its accent.")
+	}
+	tf.KeyPress(draw2dui.KeyBackspace, draw2dui.Press, 0)
+	if tf.GetString() != "ée\u0301" || tf.GetInt() != len("é") || !utf8.ValidString(tf.GetString()) {
+		t.Errorf("TextField holds %q after Backspace, should hold %q.", tf.GetString(), "ée\u0301")
+	}
+	tf.KeyPress(draw2dui.KeyRight, draw2dui.Press, 0)
+	tf.KeyPress(draw2dui.KeyBackspace, draw2dui.Press, 0)
+	if tf.GetString() != "é" {
+		t.Errorf("TextField holds %q after Backspace, should hold %q.", tf.GetString(), "é")
+	}
+	if tf.CharPress('\x07') != draw2dui.EventNone {
+		t.Error("TextField shouldn't accept control characters.")
+	}
+	tf.SetString("ééééé")
+	if tf.GetString() != "ééé" {
+		t.Errorf("SetString set %q, should cut the text short to %q.", tf.GetString(), "ééé")
+	}
+	tf.MClick(150, 15, draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	if tf.GetInt() != len("ééé") {
+		t.Error("Clicking after the text should move the cursor to its end.")
+	}
+	tf.SetInt(len("é"))
+	tf.SetString("日a")
+	if tf.GetInt() != 0 {
+		t.Errorf("SetString left the cursor at %v, should move it to the start of the character it's in.",
+			tf.GetInt())
+	}
+	tf.SetInt(len("日a"))
+	tf.SetInt(len("日a") - 2)
+	if tf.GetInt() != 0 {
+		t.Errorf("SetInt moved the cursor to %v, should move it to the start of the character it's in.",
+			tf.GetInt())
+	}
+
+	tb := NewTextBox(gc, nil, 10, 40, 100, 80, "abcdef")
+	tb.SetInt(len("abcdef"))
+	tb.SetString("日")
+	if tb.GetInt() != len("日") {
+		t.Errorf("SetString left TextBox's cursor at %v, should move it to the end of the text.", tb.GetInt())
+	}
+	tb.Draw(true, true)
+}
+
+func TestTextBoxEditing(t *testing.T) {
+	ic, gc := getHeadlessContext()
+	tb := NewTextBox(gc, nil, 10, 40, 100, 80, "abc\nd\nefgh")
+	tb.SetInt(2)
+	press := func(key draw2dui.Key) draw2dui.Event { return tb.KeyPress(key, draw2dui.Press, 0) }
+	for _, c := range []struct {
+		key draw2dui.Key
+		i   int
+	}{
+		{draw2dui.KeyDown, len("abc\nd")},
+		{draw2dui.KeyDown, len("abc\nd\nef")},
+		{draw2dui.KeyDown, len("abc\nd\nefgh")},
+		{draw2dui.KeyUp, len("abc\nd")},
+		{draw2dui.KeyUp, len("ab")},
+		{draw2dui.KeyUp, 0},
+	} {
+		if press(c.key); tb.GetInt() != c.i {
+			t.Errorf("Key %v moved the cursor to %v, should move it to %v.", c.key, tb.GetInt(), c.i)
+		}
+	}
+
+	tb.SetInt(2)
+	if press(draw2dui.KeyEnter) != draw2dui.EventAction || tb.GetString() != "ab\nc\nd\nefgh" ||
+		tb.cursor.Line() != 1 {
+		t.Errorf("TextBox holds %q after Enter, should hold %q.", tb.GetString(), "ab\nc\nd\nefgh")
+	}
+	if press(draw2dui.KeyDelete) != draw2dui.EventAction || tb.GetString() != "ab\n\nd\nefgh" {
+		t.Errorf("TextBox holds %q after Delete, should hold %q.", tb.GetString(), "ab\n\nd\nefgh")
+	}
+	tb.Draw(true, true)
+	lineHeight := tb.theme.LineHeight(*gc)
+	bottom := tb.y + float64(tb.cursor.maxLines)*lineHeight
+	if !drewInside(ic, tb) {
+		t.Error("TextBox didn't draw anything.")
+	}
+	// Lines are drawn upwards from the bottom of the TextBox, so line 0 of 4 is 3 lines above the bottom line
+	tb.MClick(11, bottom-3*lineHeight-2, draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	tb.MClick(11, bottom-3*lineHeight-2, draw2dui.MouseButtonLeft, draw2dui.Release, 0)
+	if tb.GetInt() != 0 {
+		t.Errorf("Clicking the start of the first line moved the cursor to %v, should move it to 0.", tb.GetInt())
+	}
+	tb.MClick(100, bottom-2, draw2dui.MouseButtonLeft, draw2dui.Press, 0)
+	if tb.GetInt() != len(tb.GetString()) {
+		t.Errorf("Clicking after the last line moved the cursor to %v, should move it to the end.", tb.GetInt())
+	}
+
+	long := strings.Repeat("line\n", 14) + "line"
+	tb.SetString(long)
+	tb.SetInt(len(long))
+	for i := 0; i < 10; i++ {
+		press(draw2dui.KeyUp)
+	}
+	if tb.cursor.Line() != 4 || tb.cursor.iY != 15-4-tb.cursor.maxLines {
+		t.Errorf("TextBox is scrolled %v lines up with the cursor on line %v, the cursor should be in view.",
+			tb.cursor.iY, tb.cursor.Line())
+	}
+
+	tb.SetString("ab\r\ncd")
+	if tb.cursor.textLines[0] != "ab" || tb.lineIndex(0, 1000) != len("ab") {
+		t.Errorf("Line 0 of %q is %q, the text cursor shouldn't be put between \\r and \\n.", tb.GetString(),
+			tb.cursor.textLines[0])
+	}
+	tb.SetInt(len("ab\r\ncd"))
+	if press(draw2dui.KeyUp); tb.GetInt() != len("ab") {
+		t.Errorf("Up moved the cursor to %v, should move it to the end of the first line.", tb.GetInt())
+	}
+
+	// Without a font, GenLines can't lay out any lines
+	unlaid := NewTextBox(gc, nil, 10, 40, 100, 80, "abc")
+	unlaid.cursor.textLines, unlaid.cursor.lineStarts = nil, nil
+	unlaid.showCursor()
+	unlaid.Draw(true, true)
+	if unlaid.cursor.iY != 0 {
+		t.Errorf("TextBox without any lines is scrolled %v lines up, shouldn't be scrolled.", unlaid.cursor.iY)
+	}
+
+	wrapped := strings.Repeat("word ", 10)
+	tb.SetString(wrapped)
+	tb.SetDimensions(60, 80)
+	if len(tb.cursor.textLines) < 2 || tb.GetString() != wrapped {
+		t.Error("Wrapping lines shouldn't change TextBox's text.")
+	}
+}
+
+func init() {
+	draw2d.SetFontFolder("../resource/font")
+}